@@ -0,0 +1,142 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"veda-anchor-ui/internal/logging"
+	"veda-anchor-ui/internal/settings"
+)
+
+// errAdminRequired is returned by gated bindings while an admin PIN is set
+// but has not been verified recently.
+var errAdminRequired = errors.New("admin PIN required")
+
+// adminSession is the agent's answer to a successful VerifyPin.
+type adminSession struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// requireAdmin guards bindings that change rules, pause monitoring, delete
+// data or alter retention. The agent checks the token on the same methods;
+// this only fails fast so the frontend can prompt for the PIN. Until the
+// agent has told us whether a PIN is set, the gate counts as locked.
+func (a *App) requireAdmin() error {
+	a.ensureAdminPinState()
+
+	a.adminMu.Lock()
+	defer a.adminMu.Unlock()
+	if a.adminPinKnown && !a.adminPinSet {
+		return nil
+	}
+	if a.clock.Now().After(a.adminUntil) {
+		return errAdminRequired
+	}
+	return nil
+}
+
+// ensureAdminPinState loads the PIN state if the agent hasn't answered yet.
+func (a *App) ensureAdminPinState() {
+	a.adminMu.Lock()
+	known := a.adminPinKnown
+	a.adminMu.Unlock()
+	if !known {
+		a.loadAdminPinState()
+	}
+}
+
+// loadAdminPinState asks the agent whether a PIN is configured. On failure
+// the state stays unknown and requireAdmin asks again next time.
+func (a *App) loadAdminPinState() {
+	res, err := a.ipcClient.Request("HasAdminPin", nil)
+	if err != nil {
+		logging.Component("admin").Warn("Failed to load admin PIN state", "error", err)
+		return
+	}
+	set, err := unmarshalResult[bool](res)
+	if err != nil {
+		logging.Component("admin").Warn("Failed to decode admin PIN state", "error", err)
+		return
+	}
+	a.adminMu.Lock()
+	a.adminPinSet = set
+	a.adminPinKnown = true
+	a.adminMu.Unlock()
+}
+
+// HasAdminPin reports whether a PIN is set, asking the agent if it hasn't
+// yet. If the agent can't say, it reports true so the frontend offers the
+// PIN prompt rather than an open gate.
+func (a *App) HasAdminPin() bool {
+	a.ensureAdminPinState()
+	a.adminMu.Lock()
+	defer a.adminMu.Unlock()
+	return a.adminPinSet || !a.adminPinKnown
+}
+
+// settingsNeedAdmin reports whether going from old to new weakens
+// enforcement: making the X button stop the agent, or silencing tamper
+// notifications.
+func settingsNeedAdmin(old, new settings.Settings) bool {
+	if new.CloseAction == "quit" && old.CloseAction != "quit" {
+		return true
+	}
+	wasOn, ok := old.Notifications["tamper"]
+	wasOn = wasOn || !ok
+	isOn, ok := new.Notifications["tamper"]
+	isOn = isOn || !ok
+	return wasOn && !isOn
+}
+
+// SetAdminPin sets or changes the admin PIN. The account password is
+// required; the agent stores only an argon2 hash of the PIN.
+func (a *App) SetAdminPin(password, pin string) error {
+	if len(pin) < 4 {
+		return fmt.Errorf("PIN must be at least 4 digits")
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("PIN must contain digits only")
+		}
+	}
+	if err := a.callVoid("SetAdminPin", map[string]string{"password": password, "pin": pin}); err != nil {
+		return err
+	}
+	a.adminMu.Lock()
+	a.adminPinSet = true
+	a.adminPinKnown = true
+	a.adminMu.Unlock()
+	return nil
+}
+
+// VerifyPin unlocks gated bindings until the agent-issued session expires.
+func (a *App) VerifyPin(pin string) error {
+	res, err := a.ipcClient.Request("VerifyPin", map[string]string{"pin": pin})
+	if err != nil {
+		return err
+	}
+	session, err := unmarshalResult[adminSession](res)
+	if err != nil {
+		return err
+	}
+	a.ipcClient.SetAdminToken(session.Token)
+	a.adminMu.Lock()
+	a.adminUntil = session.ExpiresAt
+	a.adminMu.Unlock()
+	return nil
+}
+
+// LockAdmin ends the admin session early.
+func (a *App) LockAdmin() {
+	a.ipcClient.SetAdminToken("")
+	a.adminMu.Lock()
+	a.adminUntil = time.Time{}
+	a.adminMu.Unlock()
+}
+
+// IsAdminUnlocked reports whether gated bindings can currently be used.
+func (a *App) IsAdminUnlocked() bool {
+	return a.requireAdmin() == nil
+}
@@ -3,14 +3,25 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"veda-anchor-ui/internal/clock"
+	"veda-anchor-ui/internal/hotkey"
+	"veda-anchor-ui/internal/i18n"
 	"veda-anchor-ui/internal/ipc"
+	"veda-anchor-ui/internal/logging"
+	"veda-anchor-ui/internal/settings"
+	"veda-anchor-ui/internal/throttle"
 
 	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
 )
@@ -19,17 +30,65 @@ import (
 type App struct {
 	ctx       context.Context
 	ipcClient *ipc.Client
+	clock     clock.Clock
+	shipper   *logging.Shipper
+	statsUser atomic.Value // string
+	settings  *settings.Store
+	heavy     *throttle.Limiter
+	quitting  atomic.Bool
+	// restarting makes shutdown launch a new UI instance.
+	restarting atomic.Bool
+	updater    *updater
+	hotkeys    *hotkey.Manager
+
+	// hidden and privacy mirror the window's visibility and whether privacy
+	// mode was started from this UI, so hotkeys can toggle them.
+	hidden  atomic.Bool
+	privacy atomic.Bool
+
+	// stop is closed when the UI shuts down so background monitors exit
+	// instead of issuing requests while the process is going away.
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	adminMu       sync.Mutex
+	adminPinSet   bool
+	adminPinKnown bool
+	adminUntil    time.Time
 }
 
 // NewApp creates a new App application struct
 func NewApp() *App {
+	store, err := settings.Open(filepath.Join(os.Getenv("LOCALAPPDATA"), "VedaAnchorUI", "settings.json"))
+	if err != nil {
+		logging.Component("settings").Warn("Using default settings", "error", err)
+	}
 	return &App{
 		ipcClient: ipc.NewClient(),
+		clock:     clock.Real{},
+		shipper:   logging.NewShipper(),
+		settings:  store,
+		updater:   newUpdater(),
+		stop:      make(chan struct{}),
+		// Per-item icon and detail lookups bypass the limit (callShared), so
+		// it only has to absorb a dashboard loading its reports at once.
+		heavy: throttle.New(5, 20),
 	}
 }
 
 // --- Helper ---
 
+// wait blocks until c fires and reports true, or returns false once the UI
+// is shutting down.
+func (a *App) wait(c <-chan time.Time) bool {
+	select {
+	case <-c:
+		return true
+	case <-a.stop:
+		return false
+	}
+}
+
 func unmarshalResult[T any](raw json.RawMessage) (T, error) {
 	var v T
 	err := json.Unmarshal(raw, &v)
@@ -51,34 +110,346 @@ func (a *App) callResult(method string, params any) (any, error) {
 	return data, err
 }
 
+// callHeavy is callResult for expensive agent work (reports, searches):
+// identical concurrent calls share one request and each method
+// is rate limited, so a runaway frontend loop cannot starve the agent.
+func (a *App) callHeavy(method string, params any) (any, error) {
+	key, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	return a.heavy.Do(method, method+string(key), func() (any, error) {
+		return a.callResult(method, params)
+	})
+}
+
+// callShared is callResult with identical concurrent calls collapsed into
+// one, but no rate limit. It is for per-item lookups (app details, icons)
+// that the frontend legitimately issues for every row of a long list.
+func (a *App) callShared(method string, params any) (any, error) {
+	key, err := json.Marshal(params)
+	if err != nil {
+		return nil, err
+	}
+	return a.heavy.Share(method+string(key), func() (any, error) {
+		return a.callResult(method, params)
+	})
+}
+
+// callStats is callHeavy for stats queries: it scopes params to the OS user
+// selected with SetStatsUser, if any.
+func (a *App) callStats(method string, params map[string]any) (any, error) {
+	if user, _ := a.statsUser.Load().(string); user != "" {
+		params["user"] = user
+	}
+	return a.callHeavy(method, params)
+}
+
+// PageQuery is the common cursor, filter and sort input for list bindings.
+// Pass the previous result's nextCursor as Cursor to fetch the next page;
+// Total is only counted when WithTotal is set, since it can be expensive.
+type PageQuery struct {
+	Cursor    string `json:"cursor,omitempty"`
+	Limit     int    `json:"limit"`
+	Since     string `json:"since,omitempty"`
+	Until     string `json:"until,omitempty"`
+	App       string `json:"app,omitempty"`
+	Domain    string `json:"domain,omitempty"`
+	Sort      string `json:"sort,omitempty"` // "newest" (default) or "oldest"
+	WithTotal bool   `json:"withTotal,omitempty"`
+}
+
+const (
+	defaultPageLimit = 100
+	maxPageLimit     = 1000
+)
+
+// callPage validates q and sends it, merged with any extra params, as a
+// paged request. The result is {items, nextCursor, total?}.
+func (a *App) callPage(method string, q PageQuery, extra map[string]any) (any, error) {
+	if q.Limit == 0 {
+		q.Limit = defaultPageLimit
+	}
+	if q.Limit < 0 || q.Limit > maxPageLimit {
+		return nil, fmt.Errorf("page limit must be between 1 and %d", maxPageLimit)
+	}
+	if q.Sort != "" && q.Sort != "newest" && q.Sort != "oldest" {
+		return nil, fmt.Errorf("unknown sort order %q", q.Sort)
+	}
+	params := map[string]any{"page": q}
+	for k, v := range extra {
+		params[k] = v
+	}
+	return a.callResult(method, params)
+}
+
+// callStatsPage is callPage for activity lists, scoped to the OS user
+// selected with SetStatsUser like callStats. Machine-wide lists (audit log,
+// webhook deliveries, tamper events) use callPage directly.
+func (a *App) callStatsPage(method string, q PageQuery, extra map[string]any) (any, error) {
+	params := map[string]any{}
+	for k, v := range extra {
+		params[k] = v
+	}
+	if user, _ := a.statsUser.Load().(string); user != "" {
+		params["user"] = user
+	}
+	return a.callPage(method, q, params)
+}
+
 // --- Stats ---
 
+// ListAppEvents pages through raw app events.
+func (a *App) ListAppEvents(q PageQuery) (any, error) {
+	return a.callStatsPage("ListAppEvents", q, nil)
+}
+
+// ListWebEvents pages through raw web visits.
+func (a *App) ListWebEvents(q PageQuery) (any, error) {
+	return a.callStatsPage("ListWebEvents", q, nil)
+}
+
+// GetTrackedUsers lists the OS accounts the agent has recorded activity for.
+func (a *App) GetTrackedUsers() (any, error) {
+	return a.callResult("GetTrackedUsers", nil)
+}
+
+// SetStatsUser limits every stats and report binding to one OS user's
+// activity on a shared machine. An empty user shows everyone.
+func (a *App) SetStatsUser(user string) {
+	a.statsUser.Store(user)
+}
+
+func (a *App) GetStatsUser() string {
+	user, _ := a.statsUser.Load().(string)
+	return user
+}
+
 func (a *App) GetAppLeaderboard(since, until string) (any, error) {
-	return a.callResult("GetAppLeaderboard", map[string]string{"since": since, "until": until})
+	return a.callStats("GetAppLeaderboard", map[string]any{"since": since, "until": until})
+}
+
+// GetAppUsageDrilldown returns the raw events behind one leaderboard entry;
+// the leaderboards themselves are served from the agent's hourly rollups.
+func (a *App) GetAppUsageDrilldown(exePath, since, until string) (any, error) {
+	return a.callStats("GetAppUsageDrilldown", map[string]any{"exePath": exePath, "since": since, "until": until})
 }
 
 func (a *App) GetScreenTime() (any, error) {
-	return a.callResult("GetScreenTime", nil)
+	return a.callStats("GetScreenTime", map[string]any{})
 }
 
 func (a *App) GetTotalScreenTime() (any, error) {
-	return a.callResult("GetTotalScreenTime", nil)
+	return a.callStats("GetTotalScreenTime", map[string]any{})
+}
+
+// GetScreenTimeBreakdown splits screen time in the range into active and idle
+// time, overall and per app.
+func (a *App) GetScreenTimeBreakdown(since, until string) (any, error) {
+	return a.callStats("GetScreenTimeBreakdown", map[string]any{"since": since, "until": until})
+}
+
+// GetSessions returns machine boot and user login/logout sessions in the
+// range, so reports can compare time powered on with time actually used.
+func (a *App) GetSessions(since, until string) (any, error) {
+	return a.callStats("GetSessions", map[string]any{"since": since, "until": until})
+}
+
+// GetTimeline returns the ordered foreground (app, title, start, end)
+// sequence in the range. Title changes shorter than the agent's minimum
+// duration are coalesced into their neighbours.
+func (a *App) GetTimeline(since, until string) (any, error) {
+	return a.callStats("GetTimeline", map[string]any{"since": since, "until": until})
+}
+
+// HeatmapFilter optionally narrows the usage heatmap to one app or category.
+type HeatmapFilter struct {
+	ExePath  string `json:"exePath,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// GetUsageHeatmap returns usage per hour-of-day x day-of-week in the range.
+// bucket is "hour" for a 7x24 grid or "day" for a calendar of daily totals.
+func (a *App) GetUsageHeatmap(since, until, bucket string, filter HeatmapFilter) (any, error) {
+	if bucket != "hour" && bucket != "day" {
+		return nil, fmt.Errorf("unknown heatmap bucket %q", bucket)
+	}
+	return a.callStats("GetUsageHeatmap", map[string]any{
+		"since": since, "until": until, "bucket": bucket, "filter": filter,
+	})
 }
 
 func (a *App) GetWebLeaderboard(since, until string) (any, error) {
-	return a.callResult("GetWebLeaderboard", map[string]string{"since": since, "until": until})
+	return a.callStats("GetWebLeaderboard", map[string]any{"since": since, "until": until})
+}
+
+// GetDomainFavicons returns the cached favicon (as a data URL) and latest page
+// title the extension reported for each domain.
+func (a *App) GetDomainFavicons(domains []string) (any, error) {
+	return a.callShared("GetDomainFavicons", map[string]any{"domains": domains})
+}
+
+// Search returns one page of app events matching query in q's range.
+func (a *App) Search(query string, q PageQuery) (any, error) {
+	return a.callStatsPage("Search", q, map[string]any{"query": query})
+}
+
+// GetWebLogs returns one page of web visits matching query in q's range.
+func (a *App) GetWebLogs(query string, q PageQuery) (any, error) {
+	return a.callStatsPage("GetWebLogs", q, map[string]any{"query": query})
 }
 
-func (a *App) Search(query, since, until string) (any, error) {
-	return a.callResult("Search", map[string]string{"query": query, "since": since, "until": until})
+// SearchAll powers the global search box: matches are grouped into apps,
+// domains, window titles and block rules, each with a count and when it was
+// last seen.
+func (a *App) SearchAll(query string) (any, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+	return a.callStats("SearchAll", map[string]any{"query": query})
 }
 
-func (a *App) GetWebLogs(query, since, until string) (any, error) {
-	return a.callResult("GetWebLogs", map[string]string{"query": query, "since": since, "until": until})
+// SearchEvents runs a full-text query over window titles, app names and URLs.
+// The query uses FTS5 syntax, so phrases and prefix matches ("invoice*") work.
+func (a *App) SearchEvents(query string, q PageQuery) (any, error) {
+	return a.callStatsPage("SearchEvents", q, map[string]any{"query": query})
 }
 
 func (a *App) GetAppDetails(exePath string) (any, error) {
-	return a.callResult("GetAppDetails", map[string]string{"exePath": exePath})
+	return a.callShared("GetAppDetails", map[string]string{"exePath": exePath})
+}
+
+// GetAppSnapshots returns the icon and display metadata the agent captured when
+// each app was first seen, so reports still render after the exe is removed.
+func (a *App) GetAppSnapshots(exePaths []string) (any, error) {
+	return a.callShared("GetAppSnapshots", map[string]any{"exePaths": exePaths})
+}
+
+// GetAppCatalog returns the agent's app catalog — product name, publisher,
+// icon, category and first-seen time per exe path — so the frontend can show
+// friendly names without extracting metadata again. An empty list returns
+// every known app.
+func (a *App) GetAppCatalog(exePaths []string) (any, error) {
+	return a.callShared("GetAppCatalog", map[string]any{"exePaths": exePaths})
+}
+
+// GetEventAncestry returns the recorded parent process chain of an app event,
+// from the direct parent up to the session root (explorer.exe, services.exe).
+func (a *App) GetEventAncestry(eventID int64) (any, error) {
+	return a.callResult("GetEventAncestry", map[string]int64{"eventId": eventID})
+}
+
+// GetIdentityAlerts lists executables whose hash changed since first sight
+// and known app names seen running from an unusual path — the usual signs of
+// a renamed binary dodging the blocklist.
+func (a *App) GetIdentityAlerts(since, until string) (any, error) {
+	return a.callStats("GetIdentityAlerts", map[string]any{"since": since, "until": until})
+}
+
+// --- Reports ---
+
+// GetDailyReport returns everything the dashboard shows for one day
+// (YYYY-MM-DD): total screen time, top apps and domains, first and last
+// activity and the category breakdown.
+func (a *App) GetDailyReport(date string) (any, error) {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+	}
+	return a.callStats("GetDailyReport", map[string]any{"date": date})
+}
+
+// GetPeriodReport aggregates screen time over the week or month containing
+// date, with deltas against the previous period and the biggest increases
+// and decreases per app and category.
+func (a *App) GetPeriodReport(period, date string) (any, error) {
+	if period != "week" && period != "month" {
+		return nil, fmt.Errorf("unknown report period %q", period)
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", date)
+	}
+	return a.callStats("GetPeriodReport", map[string]any{"period": period, "date": date})
+}
+
+// Period is a [Since, Until) range in the same format as the stats bindings.
+type Period struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
+}
+
+// CompareUsage returns side-by-side totals for two periods with the
+// percentage change, grouped by "app", "category" or "domain".
+func (a *App) CompareUsage(periodA, periodB Period, groupBy string) (any, error) {
+	switch groupBy {
+	case "app", "category", "domain":
+	default:
+		return nil, fmt.Errorf("unknown grouping %q", groupBy)
+	}
+	return a.callStats("CompareUsage", map[string]any{"periodA": periodA, "periodB": periodB, "groupBy": groupBy})
+}
+
+// --- Top-N ---
+
+// TopQuery selects a top-N list. GroupBy "category" rolls entries up into
+// their categories; empty lists individual apps, domains or titles.
+type TopQuery struct {
+	Since   string `json:"since"`
+	Until   string `json:"until"`
+	Limit   int    `json:"limit"`
+	GroupBy string `json:"groupBy,omitempty"`
+}
+
+// callTop validates q and runs a top-N query. Each entry carries its
+// duration, launch count and trend against the previous equal-length range.
+func (a *App) callTop(method string, q TopQuery) (any, error) {
+	if q.Limit <= 0 || q.Limit > 100 {
+		return nil, fmt.Errorf("top-N limit must be between 1 and 100")
+	}
+	if q.GroupBy != "" && q.GroupBy != "category" {
+		return nil, fmt.Errorf("unknown grouping %q", q.GroupBy)
+	}
+	return a.callStats(method, map[string]any{"query": q})
+}
+
+func (a *App) GetTopApps(q TopQuery) (any, error) {
+	return a.callTop("GetTopApps", q)
+}
+
+func (a *App) GetTopDomains(q TopQuery) (any, error) {
+	return a.callTop("GetTopDomains", q)
+}
+
+func (a *App) GetTopTitles(q TopQuery) (any, error) {
+	return a.callTop("GetTopTitles", q)
+}
+
+// --- App Groups ---
+
+// AppGroup makes several executables and domains count as one logical app in
+// limits and reports, e.g. Spotify.exe together with open.spotify.com.
+type AppGroup struct {
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name"`
+	ExePaths []string `json:"exePaths"`
+	Domains  []string `json:"domains"`
+}
+
+func (a *App) GetAppGroups() (any, error) {
+	return a.callResult("GetAppGroups", nil)
+}
+
+func (a *App) SaveAppGroup(group AppGroup) (any, error) {
+	if err := a.requireAdmin(); err != nil {
+		return nil, err
+	}
+	return a.callResult("SaveAppGroup", group)
+}
+
+func (a *App) DeleteAppGroup(id string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("DeleteAppGroup", map[string]string{"id": id})
 }
 
 // --- App Blocklist ---
@@ -87,15 +458,92 @@ func (a *App) GetAppBlocklist() (any, error) {
 	return a.callResult("GetAppBlocklist", nil)
 }
 
+// protectedProcesses can never be blocked; killing any of them would take down
+// the user's session or Veda Anchor itself. The agent enforces the same list,
+// this copy just rejects the request before it leaves the UI.
+var protectedProcesses = map[string]bool{
+	"explorer.exe":          true,
+	"winlogon.exe":          true,
+	"wininit.exe":           true,
+	"csrss.exe":             true,
+	"smss.exe":              true,
+	"lsass.exe":             true,
+	"services.exe":          true,
+	"svchost.exe":           true,
+	"dwm.exe":               true,
+	"sihost.exe":            true,
+	"fontdrvhost.exe":       true,
+	"logonui.exe":           true,
+	"userinit.exe":          true,
+	"ctfmon.exe":            true,
+	"taskmgr.exe":           true,
+	"veda-anchor.exe":       true,
+	"veda-anchor-ui.exe":    true,
+	"veda-anchor-agent.exe": true,
+}
+
+// protectedPrefixes extends protectedProcesses to whole families of session
+// processes, such as systemd's helpers on Linux.
+var protectedPrefixes []string
+
+func init() {
+	switch runtime.GOOS {
+	case "darwin":
+		for _, name := range []string{"loginwindow", "windowserver", "launchd"} {
+			protectedProcesses[name] = true
+		}
+	case "linux":
+		for _, name := range []string{"systemd", "systemd-logind", "systemd-journald", "systemd-udevd", "systemd-resolved", "systemd-networkd", "systemd-timesyncd"} {
+			protectedProcesses[name] = true
+		}
+		protectedPrefixes = append(protectedPrefixes, "systemd-")
+	}
+	// The user's shell: cmd.exe on Windows, $SHELL elsewhere.
+	for _, shell := range []string{os.Getenv("ComSpec"), os.Getenv("SHELL")} {
+		if shell != "" {
+			protectedProcesses[strings.ToLower(filepath.Base(shell))] = true
+		}
+	}
+}
+
+// isProtectedProcess reports whether the exe name or path is a protected
+// process.
+func isProtectedProcess(name string) bool {
+	name = strings.ToLower(filepath.Base(name))
+	if protectedProcesses[name] {
+		return true
+	}
+	for _, prefix := range protectedPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *App) BlockApps(names []string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	for _, name := range names {
+		if isProtectedProcess(name) {
+			return fmt.Errorf("%s is a protected system process and cannot be blocked", name)
+		}
+	}
 	return a.callVoid("BlockApps", names)
 }
 
 func (a *App) UnblockApps(names []string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("UnblockApps", names)
 }
 
 func (a *App) ClearAppBlocklist() error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("ClearAppBlocklist", nil)
 }
 
@@ -104,9 +552,95 @@ func (a *App) SaveAppBlocklist() (any, error) {
 }
 
 func (a *App) LoadAppBlocklist(content []byte) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("LoadAppBlocklist", content)
 }
 
+// --- Enforcement ---
+
+// Enforcement modes. Suspend and minimize keep the blocked app's unsaved
+// state but make it unusable until the block lifts.
+const (
+	EnforceKill     = "kill"
+	EnforceSuspend  = "suspend"
+	EnforceMinimize = "minimize"
+)
+
+// EnforcerOptions tunes how the agent deals with blocked apps.
+type EnforcerOptions struct {
+	// Mode is EnforceKill, EnforceSuspend or EnforceMinimize; empty means
+	// EnforceKill, as before soft blocking existed.
+	Mode string `json:"mode"`
+	// KillProcessTree terminates the whole process tree (job object on
+	// Windows) so watchdog children cannot relaunch the blocked app.
+	KillProcessTree bool `json:"killProcessTree"`
+	// A blocked app relaunched RespawnThreshold times within RespawnWindowSecs
+	// is treated as a respawn loop and retried with backoff up to MaxBackoffSecs.
+	RespawnThreshold  int `json:"respawnThreshold"`
+	RespawnWindowSecs int `json:"respawnWindowSecs"`
+	MaxBackoffSecs    int `json:"maxBackoffSecs"`
+	// GracePeriodSecs shows a countdown warning before a blocked app is
+	// terminated, so unsaved work can be saved; 0 terminates immediately.
+	GracePeriodSecs int `json:"gracePeriodSecs"`
+	// Escalation is what happens once a respawn loop is detected: "none",
+	// "notify" (alert the parent) or "lockout" (block every non-allowlisted
+	// app for LockoutMinutes).
+	Escalation     string `json:"escalation"`
+	LockoutMinutes int    `json:"lockoutMinutes"`
+}
+
+func (a *App) GetEnforcerOptions() (any, error) {
+	return a.callResult("GetEnforcerOptions", nil)
+}
+
+func (a *App) SetEnforcerOptions(opts EnforcerOptions) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if opts.Mode == "" {
+		opts.Mode = EnforceKill
+	}
+	switch opts.Mode {
+	case EnforceKill, EnforceSuspend, EnforceMinimize:
+	default:
+		return fmt.Errorf("unknown enforcement mode %q", opts.Mode)
+	}
+	if opts.GracePeriodSecs != 0 && (opts.GracePeriodSecs < 10 || opts.GracePeriodSecs > 60) {
+		return fmt.Errorf("grace period must be 0 or between 10 and 60 seconds, got %d", opts.GracePeriodSecs)
+	}
+	switch opts.Escalation {
+	case "", "none", "notify":
+	case "lockout":
+		if opts.LockoutMinutes <= 0 {
+			return fmt.Errorf("lockout escalation needs a positive duration")
+		}
+	default:
+		return fmt.Errorf("unknown escalation %q", opts.Escalation)
+	}
+	return a.callVoid("SetEnforcerOptions", opts)
+}
+
+// GetRespawnLoops lists apps the enforcer currently considers stuck in a
+// respawn loop, with their attempt counts and current backoff.
+func (a *App) GetRespawnLoops() (any, error) {
+	return a.callResult("GetRespawnLoops", nil)
+}
+
+// GetRelaunchAttempts returns, per block rule, how many times a blocked app
+// was relaunched in the range and how far enforcement escalated.
+func (a *App) GetRelaunchAttempts(since, until string) (any, error) {
+	return a.callStats("GetRelaunchAttempts", map[string]any{"since": since, "until": until})
+}
+
+// GetEnforcementHistory returns one page of enforcement actions (process
+// killed or suspended, page blocked, limit reached) with the rule that
+// triggered each, newest first.
+func (a *App) GetEnforcementHistory(q PageQuery) (any, error) {
+	return a.callPage("GetEnforcementHistory", q, nil)
+}
+
 // --- Web Blocklist ---
 
 func (a *App) GetWebBlocklist() (any, error) {
@@ -114,14 +648,23 @@ func (a *App) GetWebBlocklist() (any, error) {
 }
 
 func (a *App) AddWebBlocklist(domain string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("AddWebBlocklist", domain)
 }
 
 func (a *App) RemoveWebBlocklist(domain string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("RemoveWebBlocklist", domain)
 }
 
 func (a *App) ClearWebBlocklist() error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("ClearWebBlocklist", nil)
 }
 
@@ -130,9 +673,52 @@ func (a *App) SaveWebBlocklist() (any, error) {
 }
 
 func (a *App) LoadWebBlocklist(content []byte) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("LoadWebBlocklist", content)
 }
 
+// GetDomainBudget returns today's used and remaining time for a domain — the
+// same answer the native messaging host gives the extension for its badge.
+func (a *App) GetDomainBudget(domain string) (any, error) {
+	return a.callResult("GetDomainBudget", map[string]string{"domain": domain})
+}
+
+// --- Browser Extension ---
+
+// RegisterExtension asks the agent to write the native messaging manifest
+// allowing extensionID to connect. The manifest starts the host with an
+// explicit --native-messaging flag, so neither side has to guess from the
+// working directory or parent process whether Chrome launched it.
+func (a *App) RegisterExtension(extensionID string) error {
+	return a.callVoid("RegisterExtension", map[string]string{"extensionId": extensionID})
+}
+
+// --- Web Services ---
+
+// GetServiceMappings lists domain → service mappings (googlevideo.com →
+// YouTube), both the bundled ones and those the user added.
+func (a *App) GetServiceMappings() (any, error) {
+	return a.callResult("GetServiceMappings", nil)
+}
+
+func (a *App) AddServiceMapping(domain, service string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("AddServiceMapping", map[string]string{"domain": domain, "service": service})
+}
+
+// RemoveServiceMapping deletes a user mapping. Bundled mappings cannot be
+// removed, only overridden.
+func (a *App) RemoveServiceMapping(domain string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("RemoveServiceMapping", map[string]string{"domain": domain})
+}
+
 // --- Auth ---
 
 func (a *App) GetIsAuthenticated() (any, error) {
@@ -158,19 +744,62 @@ func (a *App) SetPassword(password string) error {
 // --- System ---
 
 func (a *App) Shutdown() error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("Shutdown", nil)
 }
 
-func (a *App) Uninstall(password string) error {
-	return a.callVoid("Uninstall", map[string]string{"password": password})
+// QuitApplication stops the agent, which drains its writer, closes open
+// intervals and disconnects the browser extension, then quits the UI. Unlike
+// the window's X button it never just hides the window.
+func (a *App) QuitApplication() error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if err := a.callVoid("Shutdown", nil); err != nil {
+		return err
+	}
+	a.Quit()
+	return nil
+}
+
+// RestartApplication restarts the agent the same way, then quits the UI and
+// starts it again.
+func (a *App) RestartApplication() error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if err := a.callVoid("Restart", nil); err != nil {
+		return err
+	}
+	a.restarting.Store(true)
+	a.Quit()
+	return nil
+}
+
+// Uninstall removes Veda Anchor. With purgeData the agent also deletes its
+// database and settings in one transaction; otherwise they are kept for a
+// later reinstall.
+func (a *App) Uninstall(password string, purgeData bool) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("Uninstall", map[string]any{"password": password, "purgeData": purgeData})
 }
 
 func (a *App) GetAutostartStatus() (any, error) {
 	return a.callResult("GetAutostartStatus", nil)
 }
 
+// autostartArgs are passed to the UI by the login entry, so it starts in
+// the tray instead of over the desktop.
+var autostartArgs = []string{"--hidden"}
+
+// EnableAutostart asks the agent to start the UI at login with
+// autostartArgs.
 func (a *App) EnableAutostart() error {
-	return a.callVoid("EnableAutostart", nil)
+	return a.callVoid("EnableAutostart", map[string]any{"args": autostartArgs})
 }
 
 func (a *App) DisableAutostart() error {
@@ -178,46 +807,1130 @@ func (a *App) DisableAutostart() error {
 }
 
 func (a *App) ClearAppHistory(password string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("ClearAppHistory", map[string]string{"password": password})
 }
 
 func (a *App) ClearWebHistory(password string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
 	return a.callVoid("ClearWebHistory", map[string]string{"password": password})
 }
 
-// --- Local Methods (UI-side only) ---
+// --- Backup ---
 
-func (a *App) CheckChromeExtension() bool {
-	progData := os.Getenv("ProgramData")
-	if progData == "" {
-		progData = `C:\ProgramData`
+// ExportBackup asks the user where to save the backup and has the agent write a
+// compressed snapshot of its database there. An empty path means the dialog was
+// cancelled.
+func (a *App) ExportBackup() (string, error) {
+	path, err := wailsruntime.SaveFileDialog(a.ctx, wailsruntime.SaveDialogOptions{
+		Title:           i18n.T("dialog.exportBackup"),
+		DefaultFilename: fmt.Sprintf("veda-anchor_backup_%s.db.gz", a.clock.Now().Format("20060102")),
+		Filters:         []wailsruntime.FileFilter{{DisplayName: i18n.T("filetype.backup"), Pattern: "*.db.gz"}},
+	})
+	if err != nil || path == "" {
+		return "", err
 	}
-	heartbeatPath := filepath.Join(progData, "VedaAnchor", "extension_heartbeat")
-	content, err := os.ReadFile(heartbeatPath)
-	if err != nil {
-		return false
+	return path, a.callVoid("ExportBackup", map[string]string{"path": path})
+}
+
+// ImportBackup lets the user pick a backup file; the agent validates it before
+// replacing the live database.
+func (a *App) ImportBackup(password string) (string, error) {
+	if err := a.requireAdmin(); err != nil {
+		return "", err
 	}
-	var lastPing int64
-	if _, err := fmt.Sscanf(string(content), "%d", &lastPing); err != nil {
-		return false
+	path, err := wailsruntime.OpenFileDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title:   i18n.T("dialog.restoreBackup"),
+		Filters: []wailsruntime.FileFilter{{DisplayName: i18n.T("filetype.backup"), Pattern: "*.db.gz"}},
+	})
+	if err != nil || path == "" {
+		return "", err
 	}
-	return time.Since(time.Unix(lastPing, 0)) < 10*time.Second
+	return path, a.callVoid("ImportBackup", map[string]string{"path": path, "password": password})
 }
 
-func (a *App) OpenBrowser(url string) error {
-	var cmd *exec.Cmd
-	switch runtime.GOOS {
-	case "windows":
-		cmd = exec.Command("cmd", "/c", "start", url)
-	case "darwin":
-		cmd = exec.Command("open", url)
-	case "linux":
-		cmd = exec.Command("xdg-open", url)
+func (a *App) GetBackupSchedule() (any, error) {
+	return a.callResult("GetBackupSchedule", nil)
+}
+
+func (a *App) SetBackupSchedule(enabled bool, folder string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
 	}
-	return cmd.Start()
+	return a.callVoid("SetBackupSchedule", map[string]any{"enabled": enabled, "folder": folder})
 }
 
-func (a *App) ShowWindow() {
-	wailsruntime.WindowUnminimise(a.ctx)
-	wailsruntime.Show(a.ctx)
+// ChooseBackupFolder opens a directory picker for the scheduled backup target.
+func (a *App) ChooseBackupFolder() (string, error) {
+	return wailsruntime.OpenDirectoryDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title:                i18n.T("dialog.chooseBackupFolder"),
+		CanCreateDirectories: true,
+	})
+}
+
+// RemoteBackupConfig describes where scheduled backups are uploaded. Kind is one
+// of "s3", "webdav" or "path"; backups are encrypted with Passphrase before they
+// leave the machine.
+type RemoteBackupConfig struct {
+	Enabled       bool   `json:"enabled"`
+	Kind          string `json:"kind"`
+	Endpoint      string `json:"endpoint"`
+	Bucket        string `json:"bucket,omitempty"`
+	Username      string `json:"username,omitempty"`
+	Secret        string `json:"secret,omitempty"`
+	Passphrase    string `json:"passphrase,omitempty"`
+	RetentionDays int    `json:"retentionDays"`
+}
+
+func (a *App) GetRemoteBackupConfig() (any, error) {
+	return a.callResult("GetRemoteBackupConfig", nil)
+}
+
+func (a *App) SetRemoteBackupConfig(cfg RemoteBackupConfig) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("SetRemoteBackupConfig", cfg)
+}
+
+// TestRemoteBackup checks the given config by uploading and removing a probe
+// object, without saving it.
+func (a *App) TestRemoteBackup(cfg RemoteBackupConfig) error {
+	return a.callVoid("TestRemoteBackup", cfg)
+}
+
+func (a *App) ListRemoteBackups() (any, error) {
+	return a.callResult("ListRemoteBackups", nil)
+}
+
+func (a *App) RestoreRemoteBackup(id, password string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("RestoreRemoteBackup", map[string]string{"id": id, "password": password})
+}
+
+// ImportDatabase lets the user pick another Veda Anchor database and has the
+// agent merge its history into the live one. The result reports how many rows
+// were added and how many were skipped as duplicates. A nil result means the
+// dialog was cancelled.
+func (a *App) ImportDatabase() (any, error) {
+	if err := a.requireAdmin(); err != nil {
+		return nil, err
+	}
+	path, err := wailsruntime.OpenFileDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title:   i18n.T("dialog.importDatabase"),
+		Filters: []wailsruntime.FileFilter{{DisplayName: i18n.T("filetype.database"), Pattern: "*.db"}},
+	})
+	if err != nil || path == "" {
+		return nil, err
+	}
+	return a.callResult("ImportDatabase", map[string]string{"path": path})
+}
+
+// --- Archives ---
+
+func (a *App) GetArchiveFolder() (any, error) {
+	return a.callResult("GetArchiveFolder", nil)
+}
+
+// ChooseArchiveFolder lets the user pick where monthly archives are written
+// and hands the folder to the agent. An empty result means it was cancelled.
+func (a *App) ChooseArchiveFolder() (string, error) {
+	folder, err := wailsruntime.OpenDirectoryDialog(a.ctx, wailsruntime.OpenDialogOptions{
+		Title:                i18n.T("dialog.chooseArchiveFolder"),
+		CanCreateDirectories: true,
+	})
+	if err != nil || folder == "" {
+		return "", err
+	}
+	return folder, a.callVoid("SetArchiveFolder", map[string]string{"folder": folder})
+}
+
+func (a *App) ListArchives() (any, error) {
+	return a.callResult("ListArchives", nil)
+}
+
+// VerifyArchive recomputes an archive's checksum and reports whether it still
+// matches the one recorded when the month was sealed.
+func (a *App) VerifyArchive(name string) (any, error) {
+	return a.callResult("VerifyArchive", map[string]string{"name": name})
+}
+
+func (a *App) ImportArchive(name string) (any, error) {
+	if err := a.requireAdmin(); err != nil {
+		return nil, err
+	}
+	return a.callResult("ImportArchive", map[string]string{"name": name})
+}
+
+// --- Data Export ---
+
+// ExportOptions selects what ExportData writes. Tables are the agent table
+// names (app_events, web_events, screen_time); Columns optionally narrows each
+// table to a subset of its columns.
+type ExportOptions struct {
+	Format  string              `json:"format"`
+	Since   string              `json:"since"`
+	Until   string              `json:"until"`
+	Tables  []string            `json:"tables"`
+	Columns map[string][]string `json:"columns,omitempty"`
+}
+
+// ExportData asks for a destination and starts the export on the agent.
+// Progress is re-emitted to the frontend as "export:progress" events.
+func (a *App) ExportData(opts ExportOptions) (string, error) {
+	if opts.Format != "csv" && opts.Format != "json" {
+		return "", fmt.Errorf("unsupported export format: %q", opts.Format)
+	}
+	path, err := wailsruntime.SaveFileDialog(a.ctx, wailsruntime.SaveDialogOptions{
+		Title:           i18n.T("dialog.exportData"),
+		DefaultFilename: "veda-anchor_export." + opts.Format,
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	jobID, err := a.startExport(path, opts)
+	if err != nil {
+		return "", err
+	}
+	go a.watchJob(jobID, "export:progress")
+	return path, nil
+}
+
+// startExport starts an export job on the agent and returns its ID. Exports
+// are heavy, so starting one goes through the same limiter as reports.
+func (a *App) startExport(path string, opts ExportOptions) (string, error) {
+	res, err := a.callHeavy("ExportData", map[string]any{"path": path, "options": opts})
+	if err != nil {
+		return "", err
+	}
+	jobID, ok := res.(string)
+	if !ok {
+		return "", fmt.Errorf("unexpected export job ID %v", res)
+	}
+	return jobID, nil
+}
+
+// --- Pairing ---
+
+// CreatePairingCode asks the agent for a short-lived pairing code. The result
+// carries the pairing URI (local endpoint + token) the frontend renders as a QR.
+func (a *App) CreatePairingCode() (any, error) {
+	return a.callResult("CreatePairingCode", nil)
+}
+
+func (a *App) CancelPairingCode(code string) error {
+	return a.callVoid("CancelPairingCode", map[string]string{"code": code})
+}
+
+func (a *App) GetPairedDevices() (any, error) {
+	return a.callResult("GetPairedDevices", nil)
+}
+
+func (a *App) RevokePairedDevice(deviceID string) error {
+	return a.callVoid("RevokePairedDevice", map[string]string{"deviceId": deviceID})
+}
+
+// --- Day Boundaries ---
+
+func (a *App) GetDayStart() (any, error) {
+	return a.callResult("GetDayStart", nil)
+}
+
+// SetDayStart sets the local hour at which a new reporting day begins (e.g. 4
+// for 4 AM) and the IANA time zone the agent should compute days in, so
+// rollups stay correct across DST changes.
+func (a *App) SetDayStart(hour int, timeZone string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if hour < 0 || hour > 23 {
+		return fmt.Errorf("day start hour must be between 0 and 23, got %d", hour)
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return fmt.Errorf("invalid time zone %q: %w", timeZone, err)
+	}
+	return a.callVoid("SetDayStart", map[string]any{"hour": hour, "timeZone": timeZone})
+}
+
+// --- Monitoring ---
+
+// MonitoringOptions controls the agent's sampling cadence. The foreground
+// window is sampled often and immediately on focus changes, while the full
+// process list is scanned on the slower interval.
+type MonitoringOptions struct {
+	ForegroundIntervalMs    int `json:"foregroundIntervalMs"`
+	ProcessScanIntervalSecs int `json:"processScanIntervalSecs"`
+	// Adaptive lets the agent stretch both intervals (up to their maximums)
+	// while on battery, idle or under high CPU load, and return to the
+	// configured values as soon as the user is active again.
+	Adaptive bool `json:"adaptive"`
+	// IdleThresholdSecs is how long without keyboard or mouse input before
+	// foreground time is recorded as idle instead of active. Zero means the
+	// default of five minutes.
+	IdleThresholdSecs int `json:"idleThresholdSecs"`
+}
+
+func (a *App) GetMonitoringOptions() (any, error) {
+	return a.callResult("GetMonitoringOptions", nil)
+}
+
+// Bounds for MonitoringOptions. Below these the agent burns CPU for no useful
+// gain; above them reports get too coarse to be meaningful.
+const (
+	minForegroundIntervalMs    = 250
+	maxForegroundIntervalMs    = 10_000
+	minProcessScanIntervalSecs = 2
+	maxProcessScanIntervalSecs = 300
+	minIdleThresholdSecs       = 30
+	maxIdleThresholdSecs       = 3600
+	defaultIdleThresholdSecs   = 300
+)
+
+func (a *App) SetMonitoringOptions(opts MonitoringOptions) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if opts.ForegroundIntervalMs < minForegroundIntervalMs || opts.ForegroundIntervalMs > maxForegroundIntervalMs {
+		return fmt.Errorf("foreground interval must be between %d and %d ms", minForegroundIntervalMs, maxForegroundIntervalMs)
+	}
+	if opts.ProcessScanIntervalSecs < minProcessScanIntervalSecs || opts.ProcessScanIntervalSecs > maxProcessScanIntervalSecs {
+		return fmt.Errorf("process scan interval must be between %d and %d seconds", minProcessScanIntervalSecs, maxProcessScanIntervalSecs)
+	}
+	if opts.IdleThresholdSecs == 0 {
+		opts.IdleThresholdSecs = defaultIdleThresholdSecs
+	}
+	if opts.IdleThresholdSecs < minIdleThresholdSecs || opts.IdleThresholdSecs > maxIdleThresholdSecs {
+		return fmt.Errorf("idle threshold must be between %d and %d seconds", minIdleThresholdSecs, maxIdleThresholdSecs)
+	}
+	return a.callVoid("SetMonitoringOptions", opts)
+}
+
+// GetMonitoringStatus reports whether monitoring is paused and, if so, when
+// it resumes.
+func (a *App) GetMonitoringStatus() (any, error) {
+	return a.callResult("GetMonitoringStatus", nil)
+}
+
+// PauseMonitoring stops process, screen time and web logging for the given
+// number of minutes. The agent records the pause in the audit log and resumes
+// on its own, even if the UI is closed in the meantime.
+func (a *App) PauseMonitoring(minutes int) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if minutes <= 0 {
+		return fmt.Errorf("pause duration must be positive, got %d minutes", minutes)
+	}
+	return a.callVoid("PauseMonitoring", map[string]int{"minutes": minutes})
+}
+
+func (a *App) ResumeMonitoring() error {
+	return a.callVoid("ResumeMonitoring", nil)
+}
+
+// StartPrivacyMode stops recording until StopPrivacyMode. Unlike a pause, the
+// agent keeps a single privacy session row so reports show the gap as
+// untracked time instead of missing time.
+func (a *App) StartPrivacyMode() error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if err := a.callVoid("StartPrivacyMode", nil); err != nil {
+		return err
+	}
+	a.privacy.Store(true)
+	return nil
+}
+
+func (a *App) StopPrivacyMode() error {
+	if err := a.callVoid("StopPrivacyMode", nil); err != nil {
+		return err
+	}
+	a.privacy.Store(false)
+	return nil
+}
+
+// GetPrivacySessions lists privacy mode gaps in the range, for reports.
+func (a *App) GetPrivacySessions(since, until string) (any, error) {
+	return a.callStats("GetPrivacySessions", map[string]any{"since": since, "until": until})
+}
+
+// --- Focus Sessions ---
+
+// FocusSession describes a focus session request. Everything not in the
+// allowlists is blocked when Strict is set, otherwise it only triggers a
+// gentle nudge.
+type FocusSession struct {
+	Minutes        int      `json:"minutes"`
+	AllowedApps    []string `json:"allowedApps"`
+	AllowedDomains []string `json:"allowedDomains"`
+	Strict         bool     `json:"strict"`
+}
+
+func (a *App) StartFocusSession(session FocusSession) error {
+	if session.Minutes <= 0 {
+		return fmt.Errorf("focus session length must be positive, got %d minutes", session.Minutes)
+	}
+	return a.callVoid("StartFocusSession", session)
+}
+
+// EndFocusSession stops the running session early; it is logged as abandoned.
+func (a *App) EndFocusSession() error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("EndFocusSession", nil)
+}
+
+func (a *App) GetFocusSession() (any, error) {
+	return a.callResult("GetFocusSession", nil)
+}
+
+// GetFocusStats returns session outcomes (completed, abandoned, distraction
+// attempts) in the range.
+func (a *App) GetFocusStats(since, until string) (any, error) {
+	return a.callStats("GetFocusStats", map[string]any{"since": since, "until": until})
+}
+
+// --- Goals ---
+
+const goalCheckInterval = time.Minute
+
+// Goal is a per-day screen time target for an app, category or domain.
+// Kind "max" is met by staying under Minutes ("less than 2h social media"),
+// "min" by reaching it ("at least 3h in the IDE").
+type Goal struct {
+	ID      string `json:"id,omitempty"`
+	Name    string `json:"name"`
+	Kind    string `json:"kind"`
+	Target  string `json:"target"`
+	Minutes int    `json:"minutes"`
+}
+
+// goalStatus is the agent's evaluation of a goal for today.
+type goalStatus struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	State string `json:"state"` // "pending", "reached" or "breached"
+}
+
+func (a *App) GetGoals() (any, error) {
+	return a.callResult("GetGoals", nil)
+}
+
+func (a *App) SaveGoal(goal Goal) (any, error) {
+	if goal.Kind != "max" && goal.Kind != "min" {
+		return nil, fmt.Errorf("unknown goal kind %q", goal.Kind)
+	}
+	if goal.Minutes <= 0 {
+		return nil, fmt.Errorf("goal minutes must be positive")
+	}
+	return a.callResult("SaveGoal", goal)
+}
+
+func (a *App) DeleteGoal(id string) error {
+	return a.callVoid("DeleteGoal", map[string]string{"id": id})
+}
+
+// GetGoalStreaks returns, per goal, the current and longest run of days on
+// which it was met.
+func (a *App) GetGoalStreaks() (any, error) {
+	return a.callResult("GetGoalStreaks", nil)
+}
+
+// watchGoals emits "goal:reached" and "goal:breached" when a goal changes
+// state, and shows a native notification for it.
+func (a *App) watchGoals() {
+	last := make(map[string]string)
+	ticker := time.NewTicker(goalCheckInterval)
+	defer ticker.Stop()
+	for ok := true; ok; ok = a.wait(ticker.C) {
+		res, err := a.ipcClient.Request("GetGoalStatus", nil)
+		if err != nil {
+			continue
+		}
+		statuses, err := unmarshalResult[[]goalStatus](res)
+		if err != nil {
+			logging.Component("goals").Warn("Failed to decode goal status", "error", err)
+			continue
+		}
+		for _, st := range statuses {
+			prev, seen := last[st.ID]
+			last[st.ID] = st.State
+			if seen && prev != st.State && st.State != "pending" {
+				wailsruntime.EventsEmit(a.ctx, "goal:"+st.State, st)
+				key := "notify.goalReached"
+				if st.State == "breached" {
+					key = "notify.goalBreached"
+				}
+				a.notify("goal", i18n.T(key+".title"), i18n.T(key+".body", st.Name))
+			}
+		}
+	}
+}
+
+// --- Break Reminders ---
+
+// BreakReminderConfig: after AfterMinutes of continuous active screen time
+// the agent reminds the user to take a BreakMinutes break.
+type BreakReminderConfig struct {
+	Enabled       bool `json:"enabled"`
+	AfterMinutes  int  `json:"afterMinutes"`
+	BreakMinutes  int  `json:"breakMinutes"`
+	SnoozeMinutes int  `json:"snoozeMinutes"`
+}
+
+func (a *App) GetBreakReminderConfig() (any, error) {
+	return a.callResult("GetBreakReminderConfig", nil)
+}
+
+func (a *App) SetBreakReminderConfig(cfg BreakReminderConfig) error {
+	if cfg.Enabled && (cfg.AfterMinutes <= 0 || cfg.BreakMinutes <= 0 || cfg.SnoozeMinutes <= 0) {
+		return fmt.Errorf("break reminder durations must be positive")
+	}
+	return a.callVoid("SetBreakReminderConfig", cfg)
+}
+
+// SnoozeBreakReminder postpones the current reminder by the configured
+// snooze time; it counts against compliance.
+func (a *App) SnoozeBreakReminder() error {
+	return a.callVoid("SnoozeBreakReminder", nil)
+}
+
+// GetBreakStats returns reminders shown, breaks taken and snoozes in the range.
+func (a *App) GetBreakStats(since, until string) (any, error) {
+	return a.callStats("GetBreakStats", map[string]any{"since": since, "until": until})
+}
+
+// --- Budget Planning ---
+
+// WeeklyBudget allocates a week's screen time across categories, in minutes.
+type WeeklyBudget struct {
+	WeekStart  string         `json:"weekStart"`
+	Categories map[string]int `json:"categories"`
+}
+
+func (a *App) GetWeeklyBudget(weekStart string) (any, error) {
+	return a.callResult("GetWeeklyBudget", map[string]string{"weekStart": weekStart})
+}
+
+func (a *App) SetWeeklyBudget(budget WeeklyBudget) error {
+	for category, minutes := range budget.Categories {
+		if minutes < 0 {
+			return fmt.Errorf("budget for %s cannot be negative", category)
+		}
+	}
+	return a.callVoid("SetWeeklyBudget", budget)
+}
+
+// GetBudgetProgress returns plan vs. actual per category for the week,
+// including the projected end-of-week burn-down.
+func (a *App) GetBudgetProgress(weekStart string) (any, error) {
+	return a.callStats("GetBudgetProgress", map[string]any{"weekStart": weekStart})
+}
+
+// --- Engagement ---
+
+func (a *App) GetEngagementClassifierEnabled() (any, error) {
+	return a.callResult("GetEngagementClassifierEnabled", nil)
+}
+
+// SetEngagementClassifierEnabled turns the on-device engagement classifier on
+// or off. It is off by default and never sends anything off the machine.
+func (a *App) SetEngagementClassifierEnabled(enabled bool) error {
+	return a.callVoid("SetEngagementClassifierEnabled", map[string]bool{"enabled": enabled})
+}
+
+// GetEngagementBlocks returns the 5-minute blocks in the range labelled as
+// engaged, passive or idle.
+func (a *App) GetEngagementBlocks(since, until string) (any, error) {
+	return a.callStats("GetEngagementBlocks", map[string]any{"since": since, "until": until})
+}
+
+// --- Experiments ---
+
+// Experiment compares enforcement variants (notification style, friction
+// level) on one rule. The agent assigns variants and measures overuse locally.
+type Experiment struct {
+	ID       string   `json:"id,omitempty"`
+	Name     string   `json:"name"`
+	RuleID   string   `json:"ruleId"`
+	Variants []string `json:"variants"`
+}
+
+func (a *App) GetExperiments() (any, error) {
+	return a.callResult("GetExperiments", nil)
+}
+
+func (a *App) CreateExperiment(exp Experiment) (any, error) {
+	if err := a.requireAdmin(); err != nil {
+		return nil, err
+	}
+	return a.callResult("CreateExperiment", exp)
+}
+
+func (a *App) StopExperiment(id string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("StopExperiment", map[string]string{"id": id})
+}
+
+func (a *App) GetExperimentResults(id string) (any, error) {
+	return a.callResult("GetExperimentResults", map[string]string{"id": id})
+}
+
+// --- Bulk Edits ---
+
+// EventSelection picks the events a bulk edit applies to. Kind is "app" or
+// "web"; App and TitleContains narrow the time range further.
+type EventSelection struct {
+	Kind          string `json:"kind"`
+	Since         string `json:"since"`
+	Until         string `json:"until"`
+	App           string `json:"app,omitempty"`
+	TitleContains string `json:"titleContains,omitempty"`
+}
+
+func (sel EventSelection) validate() error {
+	if sel.Kind != "app" && sel.Kind != "web" {
+		return fmt.Errorf("unknown event kind %q", sel.Kind)
+	}
+	if sel.Since == "" || sel.Until == "" {
+		return fmt.Errorf("bulk edits need both a start and an end time")
+	}
+	return nil
+}
+
+// DeleteEvents removes the selected events through the agent's writer and
+// records an audit entry. It returns the number of rows deleted.
+func (a *App) DeleteEvents(sel EventSelection) (any, error) {
+	if err := a.requireAdmin(); err != nil {
+		return nil, err
+	}
+	if err := sel.validate(); err != nil {
+		return nil, err
+	}
+	return a.callResult("DeleteEvents", sel)
+}
+
+// ReattributeEvents moves the selected events to another category, with an
+// audit entry. It returns the number of rows changed.
+func (a *App) ReattributeEvents(sel EventSelection, category string) (any, error) {
+	if err := a.requireAdmin(); err != nil {
+		return nil, err
+	}
+	if err := sel.validate(); err != nil {
+		return nil, err
+	}
+	return a.callResult("ReattributeEvents", map[string]any{"selection": sel, "category": category})
+}
+
+// --- Audit ---
+
+// GetAuditLog returns one page of configuration changes (settings, blocklist
+// edits, data deletions, monitoring pauses), newest first.
+func (a *App) GetAuditLog(q PageQuery) (any, error) {
+	return a.callPage("GetAuditLog", q, nil)
+}
+
+// --- Webhooks ---
+
+// Webhook is an endpoint the agent posts events to. Deliveries are signed with
+// an HMAC of the timestamp and body using the endpoint's secret and carry a
+// unique delivery ID so receivers can reject replays.
+type Webhook struct {
+	ID     string   `json:"id,omitempty"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
+func (a *App) GetWebhooks() (any, error) {
+	return a.callResult("GetWebhooks", nil)
+}
+
+// SaveWebhook creates or updates an endpoint. New endpoints get a generated
+// secret, returned once in the result.
+func (a *App) SaveWebhook(hook Webhook) (any, error) {
+	if err := a.requireAdmin(); err != nil {
+		return nil, err
+	}
+	return a.callResult("SaveWebhook", hook)
+}
+
+func (a *App) DeleteWebhook(id string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("DeleteWebhook", map[string]string{"id": id})
+}
+
+func (a *App) RotateWebhookSecret(id string) (any, error) {
+	if err := a.requireAdmin(); err != nil {
+		return nil, err
+	}
+	return a.callResult("RotateWebhookSecret", map[string]string{"id": id})
+}
+
+func (a *App) GetWebhookDeliveries(id string, q PageQuery) (any, error) {
+	return a.callPage("GetWebhookDeliveries", q, map[string]any{"id": id})
+}
+
+// RedeliverWebhook resends a past delivery with a fresh timestamp and
+// signature but the original delivery ID.
+func (a *App) RedeliverWebhook(deliveryID string) error {
+	return a.callVoid("RedeliverWebhook", map[string]string{"deliveryId": deliveryID})
+}
+
+// --- Storage ---
+
+func (a *App) GetEncryptionStatus() (any, error) {
+	return a.callResult("GetEncryptionStatus", nil)
+}
+
+// EnableEncryption re-encrypts the agent database with a key derived from the
+// admin password. The agent verifies the password before touching the file.
+func (a *App) EnableEncryption(password string) error {
+	return a.callVoid("EnableEncryption", map[string]string{"password": password})
+}
+
+func (a *App) DisableEncryption(password string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	return a.callVoid("DisableEncryption", map[string]string{"password": password})
+}
+
+// GetDatabaseStats reports database and WAL sizes along with when the agent
+// last checkpointed, analyzed and vacuumed.
+func (a *App) GetDatabaseStats() (any, error) {
+	return a.callResult("GetDatabaseStats", nil)
+}
+
+// RunMaintenance triggers the idle-time maintenance pass immediately.
+func (a *App) RunMaintenance() error {
+	return a.callVoid("RunMaintenance", nil)
+}
+
+const storageCheckInterval = 10 * time.Minute
+
+// storageStats is the agent's view of its on-disk footprint.
+type storageStats struct {
+	DatabaseBytes  int64 `json:"databaseBytes"`
+	WALBytes       int64 `json:"walBytes"`
+	ThresholdBytes int64 `json:"thresholdBytes"`
+}
+
+func (a *App) GetStorageStats() (any, error) {
+	return a.callResult("GetStorageStats", nil)
+}
+
+func (a *App) SetStorageThreshold(megabytes int) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if megabytes <= 0 {
+		return fmt.Errorf("storage threshold must be positive, got %d MB", megabytes)
+	}
+	return a.callVoid("SetStorageThreshold", map[string]int{"megabytes": megabytes})
+}
+
+// PruneDataOlderThan deletes all history older than the given number of days.
+func (a *App) PruneDataOlderThan(days int, password string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if days <= 0 {
+		return fmt.Errorf("days must be positive, got %d", days)
+	}
+	return a.callVoid("PruneDataOlderThan", map[string]any{"days": days, "password": password})
+}
+
+// watchStorage emits "storage:warning" whenever the database and WAL together
+// exceed the configured threshold.
+func (a *App) watchStorage() {
+	ticker := time.NewTicker(storageCheckInterval)
+	defer ticker.Stop()
+	for ok := true; ok; ok = a.wait(ticker.C) {
+		res, err := a.ipcClient.Request("GetStorageStats", nil)
+		if err != nil {
+			continue
+		}
+		stats, err := unmarshalResult[storageStats](res)
+		if err != nil {
+			logging.Component("storage").Warn("Failed to decode storage stats", "error", err)
+			continue
+		}
+		if stats.ThresholdBytes > 0 && stats.DatabaseBytes+stats.WALBytes > stats.ThresholdBytes {
+			wailsruntime.EventsEmit(a.ctx, "storage:warning", stats)
+		}
+	}
+}
+
+// recoveryReport is what the agent reports after its startup integrity check.
+type recoveryReport struct {
+	Recovered     bool   `json:"recovered"`
+	QuarantinedAt string `json:"quarantinedAt,omitempty"`
+	RowsSalvaged  int64  `json:"rowsSalvaged"`
+	Acknowledged  bool   `json:"acknowledged"`
+}
+
+func (a *App) GetRecoveryReport() (any, error) {
+	return a.callResult("GetRecoveryReport", nil)
+}
+
+func (a *App) AcknowledgeRecovery() error {
+	return a.callVoid("AcknowledgeRecovery", nil)
+}
+
+// checkDatabaseRecovery tells the frontend if the agent had to rebuild a
+// corrupted database since the user last looked.
+func (a *App) checkDatabaseRecovery() {
+	res, err := a.ipcClient.Request("GetRecoveryReport", nil)
+	if err != nil {
+		logging.Component("storage").Warn("Failed to fetch recovery report", "error", err)
+		return
+	}
+	report, err := unmarshalResult[recoveryReport](res)
+	if err != nil {
+		logging.Component("storage").Warn("Failed to decode recovery report", "error", err)
+		return
+	}
+	if report.Recovered && !report.Acknowledged {
+		wailsruntime.EventsEmit(a.ctx, "database:recovered", report)
+	}
+}
+
+// --- Jobs ---
+
+const jobPollInterval = 500 * time.Millisecond
+
+// jobProgress is the agent's report for a long-running job.
+type jobProgress struct {
+	JobID    string `json:"jobId"`
+	Done     int64  `json:"done"`
+	Total    int64  `json:"total"`
+	Finished bool   `json:"finished"`
+	Error    string `json:"error,omitempty"`
+}
+
+// watchJob polls the agent for a job's progress and forwards every update to
+// the frontend as the given event until the job finishes or fails.
+func (a *App) watchJob(jobID, event string) {
+	ticker := time.NewTicker(jobPollInterval)
+	defer ticker.Stop()
+	for a.wait(ticker.C) {
+		res, err := a.ipcClient.Request("GetJobProgress", map[string]string{"jobId": jobID})
+		if err != nil {
+			wailsruntime.EventsEmit(a.ctx, event, jobProgress{JobID: jobID, Finished: true, Error: err.Error()})
+			return
+		}
+		p, err := unmarshalResult[jobProgress](res)
+		if err != nil {
+			wailsruntime.EventsEmit(a.ctx, event, jobProgress{JobID: jobID, Finished: true, Error: err.Error()})
+			return
+		}
+		p.JobID = jobID
+		wailsruntime.EventsEmit(a.ctx, event, p)
+		if p.Finished {
+			return
+		}
+	}
+}
+
+func (a *App) CancelJob(jobID string) error {
+	return a.callVoid("CancelJob", map[string]string{"jobId": jobID})
+}
+
+// StartRecategorization rebuilds historical aggregates after categories or app
+// groups changed. Progress is emitted as "recategorize:progress" events and
+// the job can be stopped with CancelJob.
+func (a *App) StartRecategorization() (string, error) {
+	res, err := a.ipcClient.Request("StartRecategorization", nil)
+	if err != nil {
+		return "", err
+	}
+	jobID, err := unmarshalResult[string](res)
+	if err != nil {
+		return "", err
+	}
+	go a.watchJob(jobID, "recategorize:progress")
+	return jobID, nil
+}
+
+// --- Health ---
+
+// DaemonHealth is what the status panel shows. Subsystems is the agent's
+// per-subsystem report (process logger, screen time, writer queue, native
+// messaging, database) and is empty when the agent cannot be reached.
+type DaemonHealth struct {
+	AgentReachable bool   `json:"agentReachable"`
+	AgentError     string `json:"agentError,omitempty"`
+	Subsystems     any    `json:"subsystems,omitempty"`
+}
+
+// GetDaemonHealth never fails: an unreachable agent is itself a health result.
+func (a *App) GetDaemonHealth() DaemonHealth {
+	subsystems, err := a.callResult("GetDaemonHealth", nil)
+	if err != nil {
+		return DaemonHealth{AgentError: err.Error()}
+	}
+	return DaemonHealth{AgentReachable: true, Subsystems: subsystems}
+}
+
+// monitorRestart is emitted as "monitor:restarted" when a background monitor
+// panicked and was restarted by the supervisor.
+type monitorRestart struct {
+	Monitor string `json:"monitor"`
+	Panic   string `json:"panic"`
+}
+
+func (a *App) reportMonitorPanic(name string, recovered any, stack []byte) {
+	a.recordCrash(name, recovered, stack, false)
+	wailsruntime.EventsEmit(a.ctx, "monitor:restarted", monitorRestart{Monitor: name, Panic: fmt.Sprint(recovered)})
+}
+
+// --- Settings ---
+
+// settingsChange is the payload of the "settings_changed" event. Scope is
+// "ui" for the UI's own settings and "agent" for agent settings, in which
+// case Key names the setting that changed.
+type settingsChange struct {
+	Scope string `json:"scope"`
+	Key   string `json:"key,omitempty"`
+}
+
+// GetLocales lists the languages Go-side strings can be shown in.
+func (a *App) GetLocales() []string {
+	return i18n.Locales()
+}
+
+func (a *App) GetSettings() settings.Settings {
+	return a.settings.Get()
+}
+
+// UpdateSettings validates and saves the UI settings, then emits
+// "settings_changed" so open views and background components pick them up.
+// Changes that weaken enforcement need the admin PIN.
+func (a *App) UpdateSettings(s settings.Settings) error {
+	if settingsNeedAdmin(a.settings.Get(), s) {
+		if err := a.requireAdmin(); err != nil {
+			return err
+		}
+	}
+	return a.settings.Update(func(cur *settings.Settings) { *cur = s })
+}
+
+// applySettings keeps UI components in sync with the settings store.
+func (a *App) applySettings() {
+	cur := a.settings.Get()
+	if err := logging.SetLevel(cur.LogLevel); err != nil {
+		logging.Component("settings").Warn("Invalid log level", "error", err)
+	}
+	if err := i18n.SetLocale(cur.Locale); err != nil {
+		logging.Component("settings").Warn("Invalid locale", "error", err)
+	}
+	a.settings.Subscribe(func(old, new settings.Settings) {
+		if old.LogLevel != new.LogLevel {
+			_ = logging.SetLevel(new.LogLevel)
+		}
+		if old.Locale != new.Locale {
+			_ = i18n.SetLocale(new.Locale)
+			go func() {
+				if err := a.callVoid("SetLocale", map[string]string{"locale": new.Locale}); err != nil {
+					logging.Component("settings").Warn("Failed to pass locale to agent", "error", err)
+				}
+			}()
+		}
+		if !old.CrashReporting && new.CrashReporting {
+			go a.uploadCrashReports()
+		}
+		wailsruntime.EventsEmit(a.ctx, "settings_changed", settingsChange{Scope: "ui"})
+	})
+}
+
+func (a *App) GetAgentSettings() (any, error) {
+	return a.callResult("GetAgentSettings", nil)
+}
+
+// SetAgentSetting updates one agent setting; the agent validates the value
+// and its components react without a restart.
+func (a *App) SetAgentSetting(key string, value any) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if err := a.callVoid("SetAgentSetting", map[string]any{"key": key, "value": value}); err != nil {
+		return err
+	}
+	wailsruntime.EventsEmit(a.ctx, "settings_changed", settingsChange{Scope: "agent", Key: key})
+	return nil
+}
+
+// --- Logging ---
+
+func (a *App) GetLogLevel() string {
+	return logging.Level()
+}
+
+// SetLogLevel changes the log level of both the UI and the agent, so debug
+// logging can be turned on during a support session without a rebuild.
+func (a *App) SetLogLevel(level string) error {
+	if err := a.settings.Update(func(s *settings.Settings) { s.LogLevel = level }); err != nil {
+		return err
+	}
+	return a.callVoid("SetLogLevel", map[string]string{"level": level})
+}
+
+// GetLogs returns recent log entries from both the UI and the agent, oldest
+// first. level is the minimum level, component and since are optional filters.
+func (a *App) GetLogs(level, component string, since time.Time, limit int) ([]logging.Entry, error) {
+	entries, err := logging.Recent(level, component, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	res, err := a.ipcClient.Request("GetLogs", map[string]any{
+		"level": level, "component": component, "since": since, "limit": limit,
+	})
+	if err != nil {
+		// The agent being unreachable is often why the console was opened.
+		return entries, nil
+	}
+	agentEntries, err := unmarshalResult[[]logging.Entry](res)
+	if err != nil {
+		return nil, err
+	}
+	entries = append(entries, agentEntries...)
+	slices.SortStableFunc(entries, func(x, y logging.Entry) int { return x.Time.Compare(y.Time) })
+	if limit > 0 && len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, nil
+}
+
+func (a *App) GetLogShippingConfig() (any, error) {
+	return a.callResult("GetLogShippingConfig", nil)
+}
+
+// SetLogShippingConfig points both the agent's and the UI's WARN+ logs at a
+// remote syslog server or HTTPS collector. The agent saves the config, so
+// the local shipper only switches once the agent has accepted it.
+func (a *App) SetLogShippingConfig(cfg logging.ShipperConfig) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if err := a.callVoid("SetLogShippingConfig", cfg); err != nil {
+		return err
+	}
+	return a.shipper.Configure(cfg)
+}
+
+// loadLogShippingConfig applies the saved shipping config at startup.
+func (a *App) loadLogShippingConfig() {
+	res, err := a.ipcClient.Request("GetLogShippingConfig", nil)
+	if err != nil {
+		return
+	}
+	cfg, err := unmarshalResult[logging.ShipperConfig](res)
+	if err != nil {
+		logging.Component("app").Warn("Failed to decode log shipping config", "error", err)
+		return
+	}
+	if err := a.shipper.Configure(cfg); err != nil {
+		logging.Component("app").Warn("Invalid log shipping config", "error", err)
+	}
+}
+
+// streamLogs forwards every new UI log entry to the frontend as a "log:entry"
+// event for the live tail.
+func (a *App) streamLogs() {
+	logging.Subscribe(func(e logging.Entry) {
+		wailsruntime.EventsEmit(a.ctx, "log:entry", e)
+	})
+}
+
+// --- Local Methods (UI-side only) ---
+
+func (a *App) CheckChromeExtension() bool {
+	heartbeatPath := filepath.Join(dataDir(), "extension_heartbeat")
+	content, err := os.ReadFile(heartbeatPath)
+	if err != nil {
+		return false
+	}
+	var lastPing int64
+	if _, err := fmt.Sscanf(string(content), "%d", &lastPing); err != nil {
+		return false
+	}
+	return a.clock.Now().Sub(time.Unix(lastPing, 0)) < 10*time.Second
+}
+
+func (a *App) OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("cmd", "/c", "start", url)
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "linux":
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// beforeClose handles the window's X button according to the CloseAction
+// setting. Returning true keeps the window open.
+func (a *App) beforeClose(ctx context.Context) bool {
+	if a.quitting.Load() {
+		return false
+	}
+	switch a.settings.Get().CloseAction {
+	case "minimize":
+		wailsruntime.WindowMinimise(ctx)
+		return true
+	case "quit":
+		// Stopping the agent is gated like Shutdown; without the PIN the
+		// X button only hides the window.
+		if err := a.Shutdown(); errors.Is(err, errAdminRequired) {
+			a.hideWindow()
+			return true
+		} else if err != nil {
+			logging.Component("app").Warn("Failed to stop agent on close", "error", err)
+		}
+		return false
+	default:
+		a.hideWindow()
+		return true
+	}
+}
+
+// Quit closes the UI regardless of the close action. The agent keeps
+// monitoring; use Shutdown to stop it as well.
+func (a *App) Quit() {
+	a.quitting.Store(true)
+	wailsruntime.Quit(a.ctx)
+}
+
+func (a *App) ShowWindow() {
+	a.hidden.Store(false)
+	wailsruntime.WindowUnminimise(a.ctx)
+	wailsruntime.Show(a.ctx)
+}
+
+// hideWindow hides the window to the tray, or minimises it where there is
+// no tray to bring it back from.
+func (a *App) hideWindow() {
+	if !trayAvailable {
+		wailsruntime.WindowMinimise(a.ctx)
+		return
+	}
+	a.hidden.Store(true)
+	wailsruntime.WindowHide(a.ctx)
 }
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"veda-anchor-ui/internal/clock"
+	"veda-anchor-ui/internal/ipc"
+	"veda-anchor-ui/internal/throttle"
+)
+
+// cliCommands are the headless subcommands; the first argument selects one
+// and no window is created. They all talk to the running agent, so the
+// agent service must be up.
+var cliCommands = map[string]func(a *App, args []string, out io.Writer) error{
+	"stats":  cliStats,
+	"export": cliExport,
+	"block":  cliBlock,
+}
+
+// runCLI runs a subcommand if args names one. ok is false when args is not
+// a subcommand and the GUI should start instead.
+func runCLI(args []string) (code int, ok bool) {
+	if len(args) == 0 {
+		return 0, false
+	}
+	cmd, found := cliCommands[args[0]]
+	if !found {
+		return 0, false
+	}
+	attachConsole()
+	a := &App{ipcClient: ipc.NewClient(), clock: clock.Real{}, heavy: throttle.New(5, 20)}
+	if err := cmd(a, args[1:], os.Stdout); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintf(os.Stderr, "veda-anchor %s: %v\n", args[0], err)
+		}
+		return 1, true
+	}
+	return 0, true
+}
+
+func printJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// cliStats prints the daily report: veda-anchor stats [--today | --date YYYY-MM-DD]
+func cliStats(a *App, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	today := fs.Bool("today", false, "report on today (the default)")
+	date := fs.String("date", "", "report on this day (YYYY-MM-DD)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *today && *date != "" {
+		return fmt.Errorf("--today and --date are mutually exclusive")
+	}
+	if *date == "" {
+		*date = a.clock.Now().Format("2006-01-02")
+	}
+	report, err := a.GetDailyReport(*date)
+	if err != nil {
+		return err
+	}
+	return printJSON(out, report)
+}
+
+// cliExport writes an export without a save dialog:
+// veda-anchor export --format csv --out FILE [--since ... --until ... --tables a,b] [--timeout 30m]
+func cliExport(a *App, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	opts := ExportOptions{}
+	fs.StringVar(&opts.Format, "format", "csv", "csv or json")
+	fs.StringVar(&opts.Since, "since", "", "start of the range (inclusive)")
+	fs.StringVar(&opts.Until, "until", "", "end of the range (exclusive)")
+	path := fs.String("out", "", "destination file (required)")
+	tables := fs.String("tables", "", "comma-separated tables to export (default all)")
+	timeout := fs.Duration("timeout", 30*time.Minute, "give up if the export hasn't finished by then")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if opts.Format != "csv" && opts.Format != "json" {
+		return fmt.Errorf("unsupported export format: %q", opts.Format)
+	}
+	if *path == "" {
+		return fmt.Errorf("--out is required")
+	}
+	if *timeout <= 0 {
+		return fmt.Errorf("--timeout must be positive")
+	}
+	if *tables != "" {
+		opts.Tables = strings.Split(*tables, ",")
+	}
+	jobID, err := a.startExport(*path, opts)
+	if err != nil {
+		return err
+	}
+	deadline := a.clock.Now().Add(*timeout)
+	for {
+		if a.clock.Now().After(deadline) {
+			// Don't leave the agent writing a file nobody is waiting for.
+			_ = a.CancelJob(jobID)
+			return fmt.Errorf("export did not finish within %s", *timeout)
+		}
+		time.Sleep(jobPollInterval)
+		res, err := a.ipcClient.Request("GetJobProgress", map[string]string{"jobId": jobID})
+		if err != nil {
+			return err
+		}
+		p, err := unmarshalResult[jobProgress](res)
+		if err != nil {
+			return err
+		}
+		if p.Error != "" {
+			return errors.New(p.Error)
+		}
+		if p.Finished {
+			_, err := fmt.Fprintln(out, *path)
+			return err
+		}
+	}
+}
+
+// cliBlock manages the app blocklist:
+// veda-anchor block [--pin PIN] add|remove NAME... | list
+func cliBlock(a *App, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("block", flag.ContinueOnError)
+	pin := fs.String("pin", "", "admin PIN, if one is set")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("expected add, remove or list")
+	}
+	a.loadAdminPinState()
+	if *pin != "" {
+		if err := a.VerifyPin(*pin); err != nil {
+			return err
+		}
+	}
+	names := fs.Args()[1:]
+	switch fs.Arg(0) {
+	case "add":
+		if len(names) == 0 {
+			return fmt.Errorf("no apps given")
+		}
+		return a.BlockApps(names)
+	case "remove":
+		if len(names) == 0 {
+			return fmt.Errorf("no apps given")
+		}
+		return a.UnblockApps(names)
+	case "list":
+		list, err := a.GetAppBlocklist()
+		if err != nil {
+			return err
+		}
+		return printJSON(out, list)
+	default:
+		return fmt.Errorf("unknown block action %q", fs.Arg(0))
+	}
+}
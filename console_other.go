@@ -0,0 +1,7 @@
+//go:build !windows
+
+package main
+
+// attachConsole is only needed on Windows, where the release build has no
+// console of its own.
+func attachConsole() {}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// attachConsole connects a GUI-subsystem build to the console of the shell
+// that launched it, so CLI subcommands can print. Without a parent console
+// output is simply discarded.
+func attachConsole() {
+	const attachParentProcess = ^uintptr(0) // (DWORD)-1
+	proc := syscall.NewLazyDLL("kernel32.dll").NewProc("AttachConsole")
+	if r, _, _ := proc.Call(attachParentProcess); r == 0 {
+		return
+	}
+	if f, err := os.OpenFile("CONOUT$", os.O_WRONLY, 0); err == nil {
+		os.Stdout, os.Stderr = f, f
+	}
+}
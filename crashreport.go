@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"veda-anchor-ui/internal/crash"
+	"veda-anchor-ui/internal/logging"
+)
+
+// crashReportURL receives uploaded crash reports. It is set at build time
+// via -ldflags "-X main.crashReportURL=..."; when empty, reports stay local
+// even if the user opted in.
+var crashReportURL = ""
+
+func crashDir() crash.Dir {
+	return crash.Dir(filepath.Join(os.Getenv("LOCALAPPDATA"), "VedaAnchorUI", "crashes"))
+}
+
+// recordCrash saves a report for a panic and, if the user opted in, uploads
+// it. Fatal reports are not uploaded here: the process is about to exit, so
+// they go out at the next launch.
+func (a *App) recordCrash(component string, recovered any, stack []byte, fatal bool) {
+	if err := crashDir().Save(crash.New(a.clock.Now(), version, component, recovered, stack, fatal)); err != nil {
+		logging.Component("crash").Error("Failed to save crash report", "error", err)
+		return
+	}
+	if !fatal {
+		go a.uploadCrashReports()
+	}
+}
+
+// uploadCrashReports sends pending reports when the user has opted in.
+func (a *App) uploadCrashReports() {
+	if !a.settings.Get().CrashReporting || crashReportURL == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := crashDir().Upload(ctx, &http.Client{Timeout: 30 * time.Second}, crashReportURL); err != nil {
+		logging.Component("crash").Warn("Failed to upload crash reports", "error", err)
+	}
+}
+
+// GetCrashReports returns the locally stored crash reports, newest first,
+// exactly as they would be uploaded, so the user can review them before
+// opting in.
+func (a *App) GetCrashReports() ([]crash.Report, error) {
+	return crashDir().List()
+}
+
+// DeleteCrashReport removes a report so it is never uploaded.
+func (a *App) DeleteCrashReport(id string) error {
+	return crashDir().Delete(id)
+}
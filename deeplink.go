@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// deepLinkScheme is registered by the installer (see "protocols" in
+// wails.json), so links such as veda-anchor://rules/edit?id=42 from the
+// extension's block page or report emails launch or focus the UI.
+const deepLinkScheme = "veda-anchor"
+
+// deepLinkRoutes maps a link's first segment to the frontend route that
+// shows it. Links to anything else are rejected.
+var deepLinkRoutes = map[string]string{
+	"home":     "/",
+	"report":   "/",
+	"apps":     "/apps",
+	"rules":    "/apps",
+	"web":      "/web",
+	"settings": "/settings",
+}
+
+// DeepLink is the payload of the "navigate" event. Target is the full link
+// path ("rules/edit") for the page to act on after switching to Route.
+type DeepLink struct {
+	Route  string            `json:"route"`
+	Target string            `json:"target"`
+	Params map[string]string `json:"params"`
+}
+
+// pendingDeepLink holds a link the UI was launched with until the frontend
+// has loaded and asks for it.
+var pendingDeepLink struct {
+	sync.Mutex
+	link *DeepLink
+}
+
+// findDeepLink returns the first argument using our scheme, if any. The
+// target of "--open report/today" is treated as the same link.
+func findDeepLink(args []string) (string, bool) {
+	for i, arg := range args {
+		if strings.HasPrefix(strings.ToLower(arg), deepLinkScheme+":") {
+			return arg, true
+		}
+		if arg == "--open" && i+1 < len(args) {
+			return deepLinkScheme + "://" + args[i+1], true
+		}
+	}
+	return "", false
+}
+
+func parseDeepLink(raw string) (*DeepLink, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(u.Scheme, deepLinkScheme) {
+		return nil, fmt.Errorf("not a %s link: %q", deepLinkScheme, raw)
+	}
+	target := strings.Trim(u.Host+u.Path, "/")
+	section, _, _ := strings.Cut(target, "/")
+	route, ok := deepLinkRoutes[strings.ToLower(section)]
+	if !ok {
+		return nil, fmt.Errorf("unknown link target %q", target)
+	}
+	params := map[string]string{}
+	for k, v := range u.Query() {
+		params[k] = v[0]
+	}
+	return &DeepLink{Route: route, Target: target, Params: params}, nil
+}
+
+// openDeepLink shows the window and tells the frontend to navigate.
+func (a *App) openDeepLink(raw string) error {
+	link, err := parseDeepLink(raw)
+	if err != nil {
+		return err
+	}
+	a.ShowWindow()
+	wailsruntime.EventsEmit(a.ctx, "navigate", link)
+	return nil
+}
+
+// TakePendingDeepLink returns the link the UI was launched with, once, or
+// nil. The frontend calls it after mounting, when the "navigate" event
+// would already have been missed.
+func (a *App) TakePendingDeepLink() *DeepLink {
+	pendingDeepLink.Lock()
+	defer pendingDeepLink.Unlock()
+	link := pendingDeepLink.link
+	pendingDeepLink.link = nil
+	return link
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"veda-anchor-ui/internal/i18n"
+	"veda-anchor-ui/internal/logging"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// CreateDiagnosticsBundle asks where to save and writes a zip with everything
+// maintainers usually ask for in a bug report: UI logs, the agent's
+// diagnostics (logs, schema version, anonymized config), daemon health,
+// platform info and extension status. An empty path means it was cancelled.
+func (a *App) CreateDiagnosticsBundle() (string, error) {
+	path, err := wailsruntime.SaveFileDialog(a.ctx, wailsruntime.SaveDialogOptions{
+		Title:           i18n.T("dialog.saveDiagnostics"),
+		DefaultFilename: fmt.Sprintf("veda-anchor_diagnostics_%s.zip", a.clock.Now().Format("20060102-150405")),
+		Filters:         []wailsruntime.FileFilter{{DisplayName: i18n.T("filetype.zip"), Pattern: "*.zip"}},
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	zw := zip.NewWriter(f)
+
+	env := wailsruntime.Environment(a.ctx)
+	platform := map[string]any{
+		"os":        runtime.GOOS,
+		"arch":      runtime.GOARCH,
+		"goVersion": runtime.Version(),
+		"uiVersion": version,
+		"buildType": env.BuildType,
+		"platform":  env.Platform,
+	}
+	if err := writeJSON(zw, "platform.json", platform); err != nil {
+		return "", err
+	}
+	if err := writeJSON(zw, "health.json", a.GetDaemonHealth()); err != nil {
+		return "", err
+	}
+	if err := writeJSON(zw, "extension.json", map[string]bool{"connected": a.CheckChromeExtension()}); err != nil {
+		return "", err
+	}
+
+	// The agent anonymizes its own config; a failure here is recorded in the
+	// bundle rather than aborting it, since a dead agent is worth reporting.
+	agent, err := a.callResult("GetDiagnostics", nil)
+	if err != nil {
+		agent = map[string]string{"error": err.Error()}
+	}
+	if err := writeJSON(zw, "agent.json", agent); err != nil {
+		return "", err
+	}
+
+	logs, _ := filepath.Glob(filepath.Join(logDir(), uiLogName+"*"))
+	for _, l := range logs {
+		if err := copyFile(zw, "logs/"+filepath.Base(l), l); err != nil {
+			logging.Component("diagnostics").Warn("Skipping log file", "path", l, "error", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func copyFile(zw *zip.Writer, name, path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
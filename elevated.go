@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"veda-anchor-ui/internal/elevate"
+	"veda-anchor-ui/internal/logging"
+)
+
+// elevatedTaskFlag makes the executable run one task from elevatedTasks and
+// exit. RequestElevation launches it through UAC, so only the task runs
+// with administrator rights, never the UI.
+const elevatedTaskFlag = "--elevated-task"
+
+// elevatedTasks are the only operations an elevated instance will perform.
+// Each validates its own arguments; the elevated process trusts nothing
+// from the unelevated one.
+var elevatedTasks = map[string]func(args []string) error{
+	"hosts-file":        applyHostsFile,
+	"register-protocol": registerProtocol,
+}
+
+// runElevatedTask runs a task when args start with elevatedTaskFlag. ok is
+// false otherwise.
+func runElevatedTask(args []string) (code int, ok bool) {
+	if len(args) < 2 || args[0] != elevatedTaskFlag {
+		return 0, false
+	}
+	logger := logging.Component("elevated")
+	task, found := elevatedTasks[args[1]]
+	if !found {
+		logger.Error("Unknown elevated task", "task", args[1])
+		return 2, true
+	}
+	if err := task(args[2:]); err != nil {
+		logger.Error("Elevated task failed", "task", args[1], "error", err)
+		return 1, true
+	}
+	logger.Info("Elevated task completed", "task", args[1])
+	return 0, true
+}
+
+// RequestElevation runs one scoped administrator task after a UAC prompt:
+// "hosts-file" with the domains to block (none clears the block), or
+// "register-protocol" to register veda-anchor:// links for all users.
+func (a *App) RequestElevation(task string, args []string) error {
+	if err := a.requireAdmin(); err != nil {
+		return err
+	}
+	if _, ok := elevatedTasks[task]; !ok {
+		return fmt.Errorf("unknown elevated task %q", task)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	code, err := elevate.Run(exe, append([]string{elevatedTaskFlag, task}, args...))
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("elevated task %s failed with exit code %d; see the UI log for details", task, code)
+	}
+	return nil
+}
+
+const (
+	hostsBegin = "# BEGIN veda-anchor"
+	hostsEnd   = "# END veda-anchor"
+)
+
+var hostnamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)+$`)
+
+func hostsPath() string {
+	if runtime.GOOS == "windows" {
+		root := os.Getenv("SystemRoot")
+		if root == "" {
+			root = `C:\Windows`
+		}
+		return filepath.Join(root, "System32", "drivers", "etc", "hosts")
+	}
+	return "/etc/hosts"
+}
+
+// applyHostsFile replaces our marked section of the hosts file with entries
+// pointing each domain at 0.0.0.0. This blocks sites in every browser, not
+// only those with the extension.
+func applyHostsFile(domains []string) error {
+	var entries []string
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if !hostnamePattern.MatchString(d) {
+			return fmt.Errorf("invalid domain %q", d)
+		}
+		entries = append(entries, "0.0.0.0 "+d)
+	}
+	path := hostsPath()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var kept []string
+	inSection := false
+	for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+		switch strings.TrimSpace(line) {
+		case hostsBegin:
+			inSection = true
+			continue
+		case hostsEnd:
+			inSection = false
+			continue
+		}
+		if !inSection {
+			kept = append(kept, line)
+		}
+	}
+	for len(kept) > 0 && kept[len(kept)-1] == "" {
+		kept = kept[:len(kept)-1]
+	}
+	if len(entries) > 0 {
+		kept = append(kept, "", hostsBegin)
+		kept = append(kept, entries...)
+		kept = append(kept, hostsEnd)
+	}
+	eol := "\n"
+	if runtime.GOOS == "windows" {
+		eol = "\r\n"
+	}
+	return os.WriteFile(path, []byte(strings.Join(kept, eol)+eol), 0644)
+}
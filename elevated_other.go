@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+import "veda-anchor-ui/internal/elevate"
+
+func registerProtocol(args []string) error {
+	return elevate.ErrUnsupported
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// registerProtocol registers the deep link scheme machine-wide, for users
+// other than the one who ran the installer.
+func registerProtocol(args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("register-protocol takes no arguments")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	values := []struct{ path, name, value string }{
+		{`Software\Classes\` + deepLinkScheme, "", "Veda Anchor"},
+		{`Software\Classes\` + deepLinkScheme, "URL Protocol", ""},
+		{`Software\Classes\` + deepLinkScheme + `\DefaultIcon`, "", exe + ",0"},
+		{`Software\Classes\` + deepLinkScheme + `\shell\open\command`, "", `"` + exe + `" "%1"`},
+	}
+	for _, v := range values {
+		k, _, err := registry.CreateKey(registry.LOCAL_MACHINE, v.path, registry.SET_VALUE)
+		if err != nil {
+			return err
+		}
+		err = k.SetStringValue(v.name, v.value)
+		_ = k.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
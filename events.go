@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"time"
+
+	"veda-anchor-ui/internal/ipc"
+	"veda-anchor-ui/internal/logging"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	// eventPollWait is how long the agent may hold a PollEvents request open
+	// when it has nothing new to report.
+	eventPollWait  = 25 * time.Second
+	eventPollRetry = 2 * time.Second
+)
+
+// dataEvents are bus events announcing newly written rows. They are also
+// emitted under their bare name with just the compact row payload, so views
+// can refresh within a second instead of polling.
+var dataEvents = map[string]bool{
+	"app_event_created":   true,
+	"web_event_created":   true,
+	"screen_time_updated": true,
+}
+
+// activityEvent is one entry of the agent's live event bus: app launches,
+// foreground changes, blocks and web visits.
+type activityEvent struct {
+	Seq  int64           `json:"seq"`
+	Type string          `json:"type"`
+	Time time.Time       `json:"time"`
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// pumpEvents long-polls the agent's event bus and re-emits every event to the
+// frontend as "activity" and "activity:<type>". It uses its own connection so
+// a held poll never delays regular bindings.
+func (a *App) pumpEvents() {
+	client := ipc.NewClient()
+	logger := logging.Component("events")
+	var cursor int64
+	for {
+		select {
+		case <-a.stop:
+			return
+		default:
+		}
+		res, err := client.Request("PollEvents", map[string]any{"after": cursor, "waitMs": eventPollWait.Milliseconds()})
+		if err != nil {
+			logger.Debug("Event poll failed", "error", err)
+			time.Sleep(eventPollRetry)
+			continue
+		}
+		events, err := unmarshalResult[[]activityEvent](res)
+		if err != nil {
+			logger.Warn("Failed to decode events", "error", err)
+			time.Sleep(eventPollRetry)
+			continue
+		}
+		for _, e := range events {
+			cursor = e.Seq
+			wailsruntime.EventsEmit(a.ctx, "activity", e)
+			wailsruntime.EventsEmit(a.ctx, "activity:"+e.Type, e)
+			if dataEvents[e.Type] {
+				wailsruntime.EventsEmit(a.ctx, e.Type, e.Data)
+			}
+			a.notifyEvent(e)
+		}
+	}
+}
+
+// GetEventStreamEndpoint returns the local WebSocket URL where the agent
+// publishes the same events, for tools outside the UI.
+func (a *App) GetEventStreamEndpoint() (any, error) {
+	return a.callResult("GetEventStreamEndpoint", nil)
+}
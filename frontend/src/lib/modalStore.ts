@@ -1,7 +1,11 @@
 import { get, writable } from 'svelte/store';
 import { showToast } from './toastStore';
 
-export type ConfirmAction = 'uninstall' | 'clearAppHistory' | 'clearWebHistory';
+export type ConfirmAction =
+  | 'uninstall'
+  | 'clearAppHistory'
+  | 'clearWebHistory'
+  | 'quit';
 
 export const isConfirmModalOpen = writable(false);
 export const confirmModalPassword = writable('');
@@ -34,14 +38,19 @@ export async function handleConfirmSubmit() {
 
   try {
     switch (action) {
-      case 'uninstall':
-        await window.go.main.App.Uninstall(password);
+      case 'uninstall': {
+        // OK keeps the data, like the installer's default.
+        const keepData = confirm(
+          'Giữ lại lịch sử và cài đặt của Veda Anchor cho lần cài đặt sau? Chọn "Hủy" để xóa toàn bộ dữ liệu.',
+        );
+        await window.go.main.App.Uninstall(password, !keepData);
         isConfirmModalOpen.set(false);
         // Give the modal a moment to close before closing the page
         setTimeout(() => {
           window.location.href = 'about:blank';
         }, 500);
         break;
+      }
 
       case 'clearAppHistory':
         await window.go.main.App.ClearAppHistory(password);
@@ -54,6 +63,14 @@ export async function handleConfirmSubmit() {
         isConfirmModalOpen.set(false);
         showToast('Đã xóa lịch sử duyệt web.', 'success');
         break;
+
+      case 'quit':
+        // The password here is the admin PIN; a verified PIN opens the
+        // admin session QuitApplication checks.
+        await window.go.main.App.VerifyPin(password);
+        await window.go.main.App.QuitApplication();
+        isConfirmModalOpen.set(false);
+        break;
     }
   } catch (error) {
     console.error(`Action ${action} failed:`, error);
@@ -13,6 +13,30 @@ export function navigate(path: string) {
   currentPath.set(path);
 }
 
+export interface DeepLink {
+  route: string;
+  target: string;
+  params: Record<string, string>;
+}
+
+// The last veda-anchor:// link opened, for the target page to act on
+// (e.g. open the rule editor for params.id). The page clears it once read.
+export const deepLink = writable<DeepLink | null>(null);
+
+// Sections whose pages read deepLink; other links only switch the route.
+const pageSections = ['rules', 'report'];
+
+export function openDeepLink(link: DeepLink) {
+  deepLink.set(pageSections.includes(linkSection(link)) ? link : null);
+  navigate(link.route);
+}
+
+// linkSection is the first segment of a link's target ("rules" for
+// rules/edit), which tells the page on that route what to open.
+export function linkSection(link: DeepLink): string {
+  return link.target.split('/')[0].toLowerCase();
+}
+
 // Listen for hash changes and update the store
 window.addEventListener('hashchange', () => {
   currentPath.set(getHashPath());
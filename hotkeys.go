@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"veda-anchor-ui/internal/hotkey"
+	"veda-anchor-ui/internal/logging"
+	"veda-anchor-ui/internal/settings"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// hotkeyFocusMinutes is the length of a focus session started by hotkey.
+const hotkeyFocusMinutes = 25
+
+// startHotkeys registers the configured global hotkeys and re-registers
+// them whenever the settings change.
+func (a *App) startHotkeys() {
+	a.hotkeys = hotkey.New(func(action string) {
+		// Called on the hotkey thread, which must keep pumping messages.
+		go a.runHotkeyAction(action)
+	})
+	if err := a.hotkeys.Set(parseHotkeys(a.settings.Get().Hotkeys)); err != nil {
+		logging.Component("hotkey").Warn("Failed to register hotkeys", "error", err)
+	}
+	a.settings.Subscribe(func(old, new settings.Settings) {
+		if maps.Equal(old.Hotkeys, new.Hotkeys) {
+			return
+		}
+		if err := a.hotkeys.Set(parseHotkeys(new.Hotkeys)); err != nil {
+			logging.Component("hotkey").Warn("Failed to register hotkeys", "error", err)
+		}
+	})
+}
+
+// parseHotkeys converts validated settings to bindings, skipping unbound
+// actions.
+func parseHotkeys(combos map[string]string) map[string]hotkey.Hotkey {
+	bindings := map[string]hotkey.Hotkey{}
+	for action, combo := range combos {
+		if h, err := hotkey.Parse(combo); err == nil {
+			bindings[action] = h
+		}
+	}
+	return bindings
+}
+
+func (a *App) runHotkeyAction(action string) {
+	var err error
+	switch action {
+	case "toggleWindow":
+		if a.hidden.Load() {
+			a.ShowWindow()
+		} else {
+			a.hideWindow()
+		}
+	case "togglePrivacy":
+		if a.privacy.Load() {
+			err = a.StopPrivacyMode()
+		} else {
+			err = a.StartPrivacyMode()
+		}
+	case "startFocus":
+		err = a.StartFocusSession(FocusSession{Minutes: hotkeyFocusMinutes})
+	}
+	if err != nil {
+		logging.Component("hotkey").Warn("Hotkey action failed", "action", action, "error", err)
+		wailsruntime.EventsEmit(a.ctx, "hotkey:failed", map[string]string{"action": action, "error": err.Error()})
+	}
+}
+
+// GetHotkeyActions lists the actions that can be bound, for the settings page.
+func (a *App) GetHotkeyActions() []string {
+	return settings.HotkeyActions
+}
+
+// GetHotkeys returns the current bindings by action.
+func (a *App) GetHotkeys() map[string]string {
+	return a.settings.Get().Hotkeys
+}
+
+// SetHotkey binds action to combo (as captured by the settings page, e.g.
+// "Ctrl+Shift+F"), or unbinds it when combo is empty. The new set is
+// validated, then registered before it is saved, so a combination bound
+// twice or already owned by another application is reported and the
+// previous bindings are kept.
+func (a *App) SetHotkey(action, combo string) error {
+	if !slices.Contains(settings.HotkeyActions, action) {
+		return fmt.Errorf("unknown hotkey action %q", action)
+	}
+	if combo != "" {
+		h, err := hotkey.Parse(combo)
+		if err != nil {
+			return err
+		}
+		combo = h.String()
+	}
+	cur := a.settings.Get()
+	next := maps.Clone(cur.Hotkeys)
+	if next == nil {
+		next = map[string]string{}
+	}
+	next[action] = combo
+	check := cur
+	check.Hotkeys = next
+	if err := check.Validate(); err != nil {
+		return err
+	}
+	if err := a.hotkeys.Set(parseHotkeys(next)); err != nil {
+		_ = a.hotkeys.Set(parseHotkeys(cur.Hotkeys))
+		return err
+	}
+	return a.settings.Update(func(s *settings.Settings) { s.Hotkeys = next })
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"veda-anchor-ui/internal/logging"
+
+	"github.com/wailsapp/wails/v2/pkg/options"
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+// instanceCommand is an action requested on the command line, so shortcuts
+// and scripts can control the running UI: veda-anchor-ui.exe --pause 60.
+// --open is handled as a deep link (see findDeepLink).
+type instanceCommand struct {
+	flag    string
+	minutes int
+}
+
+// parseInstanceCommands picks the command flags out of args. Other flags,
+// such as --hidden, are left for their own handlers.
+func parseInstanceCommands(args []string) ([]instanceCommand, error) {
+	var cmds []instanceCommand
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--pause":
+			if i+1 == len(args) {
+				return nil, fmt.Errorf("--pause needs a number of minutes")
+			}
+			i++
+			minutes, err := strconv.Atoi(args[i])
+			if err != nil || minutes <= 0 {
+				return nil, fmt.Errorf("invalid --pause minutes %q", args[i])
+			}
+			cmds = append(cmds, instanceCommand{flag: "--pause", minutes: minutes})
+		case "--resume", "--show", "--hide", "--quit":
+			cmds = append(cmds, instanceCommand{flag: args[i]})
+		}
+	}
+	return cmds, nil
+}
+
+// runInstanceCommands performs cmds in order. Failures are logged and sent
+// to the frontend as "command:failed".
+func (a *App) runInstanceCommands(cmds []instanceCommand) {
+	for _, c := range cmds {
+		var err error
+		switch c.flag {
+		case "--pause":
+			err = a.PauseMonitoring(c.minutes)
+		case "--resume":
+			err = a.ResumeMonitoring()
+		case "--show":
+			a.ShowWindow()
+		case "--hide":
+			a.hideWindow()
+		case "--quit":
+			a.Quit()
+		}
+		if err != nil {
+			logging.Component("app").Warn("Command line action failed", "command", c.flag, "error", err)
+			wailsruntime.EventsEmit(a.ctx, "command:failed", map[string]string{"command": c.flag, "error": err.Error()})
+		}
+	}
+}
+
+// onSecondInstance handles another launch while we are running: a deep link
+// or --open navigates, command flags are run, and a plain launch just
+// brings the window forward.
+func (a *App) onSecondInstance(data options.SecondInstanceData) {
+	logger := logging.Component("app")
+	cmds, err := parseInstanceCommands(data.Args)
+	if err != nil {
+		logger.Warn("Ignoring second instance arguments", "args", data.Args, "error", err)
+	}
+	raw, hasLink := findDeepLink(data.Args)
+	if hasLink {
+		logger.Info("Second GUI instance opened a deep link", "link", raw)
+		if err := a.openDeepLink(raw); err != nil {
+			logger.Warn("Ignoring deep link", "link", raw, "error", err)
+			a.ShowWindow()
+		}
+	}
+	if len(cmds) > 0 {
+		logger.Info("Second GUI instance sent commands", "args", data.Args)
+		a.runInstanceCommands(cmds)
+		return
+	}
+	if !hasLink {
+		logger.Info("Second GUI instance detected - showing existing window")
+		a.ShowWindow()
+	}
+}
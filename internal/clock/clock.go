@@ -0,0 +1,80 @@
+// Package clock abstracts the wall clock so time-dependent UI logic (heartbeat
+// freshness, dated file names, schedules, rate limits, clock tamper
+// detection) can be driven by a controllable fake.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock reports the current time.
+type Clock interface {
+	Now() time.Time
+	// Monotonic returns time elapsed on a clock the user can't change, for
+	// telling real elapsed time apart from the wall clock being reset.
+	Monotonic() time.Duration
+}
+
+// Real is the system clock.
+type Real struct{}
+
+// start anchors Real's monotonic readings.
+var start = time.Now()
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+func (Real) Monotonic() time.Duration {
+	return time.Since(start)
+}
+
+// Fake is a Clock whose time only changes when told to.
+type Fake struct {
+	mu   sync.Mutex
+	now  time.Time
+	mono time.Duration
+}
+
+// NewFake returns a Fake clock set to t.
+func NewFake(t time.Time) *Fake {
+	return &Fake{now: t}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) Monotonic() time.Duration {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.mono
+}
+
+// Set jumps the wall clock to t, which may be earlier than the current time,
+// as when the user changes the system time. The monotonic clock is not
+// affected.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = t
+}
+
+// Advance moves both clocks forward by d.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+	f.mono += d
+}
+
+// Suspend moves the wall clock forward by d without the monotonic clock, as
+// when the machine sleeps and the monotonic clock stops counting.
+func (f *Fake) Suspend(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
@@ -0,0 +1,29 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFake(t *testing.T) {
+	base := time.Date(2026, 10, 16, 23, 59, 0, 0, time.UTC)
+	f := NewFake(base)
+
+	f.Advance(2 * time.Minute)
+	if got := f.Now(); got.Day() != 17 || got.Hour() != 0 || got.Minute() != 1 {
+		t.Errorf("after midnight rollover Now() = %v", got)
+	}
+	if got := f.Monotonic(); got != 2*time.Minute {
+		t.Errorf("Monotonic() = %v, want 2m", got)
+	}
+
+	f.Suspend(time.Hour)
+	if got := f.Monotonic(); got != 2*time.Minute {
+		t.Errorf("Suspend moved the monotonic clock to %v", got)
+	}
+
+	f.Set(base)
+	if !f.Now().Equal(base) || f.Monotonic() != 2*time.Minute {
+		t.Errorf("Set changed the monotonic clock or missed the wall clock")
+	}
+}
@@ -0,0 +1,165 @@
+// Package crash writes a local report for every panic the UI recovers from
+// or dies of, and uploads them only when the user has opted in. Reports hold
+// the stack, build and OS — never activity data — and the user can review
+// exactly what would be sent before agreeing.
+package crash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// maxReports bounds the folder; the oldest reports are deleted first.
+const maxReports = 20
+
+// Report describes one panic.
+type Report struct {
+	ID        string    `json:"id"`
+	Time      time.Time `json:"time"`
+	Version   string    `json:"version"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	Component string    `json:"component"`
+	Panic     string    `json:"panic"`
+	Stack     string    `json:"stack"`
+	// Fatal is true when the panic ended the process rather than being
+	// recovered by a supervisor.
+	Fatal    bool `json:"fatal"`
+	Uploaded bool `json:"uploaded"`
+}
+
+// New builds a report, dated at, for a recovered value and its stack.
+func New(at time.Time, version, component string, recovered any, stack []byte, fatal bool) Report {
+	now := at.UTC()
+	return Report{
+		ID:        fmt.Sprintf("%s-%s", now.Format("20060102T150405.000"), component),
+		Time:      now,
+		Version:   version,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Component: component,
+		Panic:     fmt.Sprint(recovered),
+		Stack:     scrubPaths(string(stack)),
+		Fatal:     fatal,
+	}
+}
+
+// scrubPaths drops the user's home directory from stack file paths, which
+// would otherwise reveal their account name.
+func scrubPaths(stack string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return stack
+	}
+	stack = strings.ReplaceAll(stack, home, "~")
+	return strings.ReplaceAll(stack, filepath.ToSlash(home), "~")
+}
+
+// Dir stores reports as one JSON file each.
+type Dir string
+
+// Save writes r and prunes the oldest reports beyond maxReports.
+func (d Dir) Save(r Report) error {
+	if err := os.MkdirAll(string(d), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(d.path(r.ID), data, 0644); err != nil {
+		return err
+	}
+	reports, err := d.List()
+	if err != nil {
+		return err
+	}
+	for _, old := range reports[min(len(reports), maxReports):] {
+		_ = os.Remove(d.path(old.ID))
+	}
+	return nil
+}
+
+// List returns all reports, newest first. Unreadable files are skipped.
+func (d Dir) List() ([]Report, error) {
+	entries, err := os.ReadDir(string(d))
+	if os.IsNotExist(err) {
+		return []Report{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	reports := []Report{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(string(d), e.Name()))
+		if err != nil {
+			continue
+		}
+		var r Report
+		if json.Unmarshal(data, &r) == nil {
+			reports = append(reports, r)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Time.After(reports[j].Time) })
+	return reports, nil
+}
+
+// Delete removes one report.
+func (d Dir) Delete(id string) error {
+	if id != filepath.Base(id) {
+		return fmt.Errorf("invalid report id %q", id)
+	}
+	return os.Remove(d.path(id))
+}
+
+func (d Dir) path(id string) string {
+	return filepath.Join(string(d), id+".json")
+}
+
+// Upload posts every report not yet uploaded to url as JSON and marks it
+// uploaded. It stops at the first failure so the rest are retried later.
+func (d Dir) Upload(ctx context.Context, client *http.Client, url string) error {
+	reports, err := d.List()
+	if err != nil {
+		return err
+	}
+	for _, r := range reports {
+		if r.Uploaded {
+			continue
+		}
+		body, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("crash upload returned %s", resp.Status)
+		}
+		r.Uploaded = true
+		if err := d.Save(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
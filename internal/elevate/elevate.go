@@ -0,0 +1,13 @@
+// Package elevate runs a program with administrator rights through the UAC
+// prompt and waits for it, so a single privileged step doesn't require the
+// whole UI to run elevated.
+package elevate
+
+import "errors"
+
+var (
+	// ErrCancelled means the user declined the UAC prompt.
+	ErrCancelled = errors.New("elevation was cancelled")
+	// ErrUnsupported is returned on platforms without UAC.
+	ErrUnsupported = errors.New("elevation is only supported on Windows")
+)
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package elevate
+
+func Run(exe string, args []string) (int, error) {
+	return 0, ErrUnsupported
+}
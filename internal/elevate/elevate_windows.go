@@ -0,0 +1,78 @@
+package elevate
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	seeMaskNoCloseProcess = 0x40
+	seeMaskNoAsync        = 0x100
+	errorCancelled        = syscall.Errno(1223)
+)
+
+var procShellExecuteEx = syscall.NewLazyDLL("shell32.dll").NewProc("ShellExecuteExW")
+
+// shellExecuteInfo is SHELLEXECUTEINFOW.
+type shellExecuteInfo struct {
+	cbSize         uint32
+	fMask          uint32
+	hwnd           uintptr
+	lpVerb         *uint16
+	lpFile         *uint16
+	lpParameters   *uint16
+	lpDirectory    *uint16
+	nShow          int32
+	hInstApp       uintptr
+	lpIDList       uintptr
+	lpClass        *uint16
+	hkeyClass      uintptr
+	dwHotKey       uint32
+	hIconOrMonitor uintptr
+	hProcess       syscall.Handle
+}
+
+// Run starts exe with args elevated, hidden, and returns its exit code once
+// it finishes.
+func Run(exe string, args []string) (int, error) {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = syscall.EscapeArg(a)
+	}
+	verb, err := syscall.UTF16PtrFromString("runas")
+	if err != nil {
+		return 0, err
+	}
+	file, err := syscall.UTF16PtrFromString(exe)
+	if err != nil {
+		return 0, err
+	}
+	params, err := syscall.UTF16PtrFromString(strings.Join(quoted, " "))
+	if err != nil {
+		return 0, err
+	}
+	info := shellExecuteInfo{
+		fMask:        seeMaskNoCloseProcess | seeMaskNoAsync,
+		lpVerb:       verb,
+		lpFile:       file,
+		lpParameters: params,
+	}
+	info.cbSize = uint32(unsafe.Sizeof(info))
+	if r, _, err := procShellExecuteEx.Call(uintptr(unsafe.Pointer(&info))); r == 0 {
+		if errors.Is(err, errorCancelled) {
+			return 0, ErrCancelled
+		}
+		return 0, err
+	}
+	defer func() { _ = syscall.CloseHandle(info.hProcess) }()
+	if _, err := syscall.WaitForSingleObject(info.hProcess, syscall.INFINITE); err != nil {
+		return 0, err
+	}
+	var code uint32
+	if err := syscall.GetExitCodeProcess(info.hProcess, &code); err != nil {
+		return 0, err
+	}
+	return int(code), nil
+}
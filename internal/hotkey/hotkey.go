@@ -0,0 +1,121 @@
+// Package hotkey parses key combinations such as "Ctrl+Shift+F" and
+// registers them as system-wide hotkeys that work while the window is
+// hidden or another application has focus.
+package hotkey
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Modifier flags, matching the Win32 MOD_* values.
+const (
+	ModAlt   = 0x1
+	ModCtrl  = 0x2
+	ModShift = 0x4
+	ModWin   = 0x8
+)
+
+// ErrUnsupported is returned when registering hotkeys on a platform
+// without an implementation.
+var ErrUnsupported = errors.New("global hotkeys are not supported on this platform")
+
+// Hotkey is a parsed combination. Key is a Windows virtual-key code.
+type Hotkey struct {
+	Mods uint32
+	Key  uint32
+}
+
+var modNames = []struct {
+	name string
+	mod  uint32
+}{
+	{"Ctrl", ModCtrl},
+	{"Alt", ModAlt},
+	{"Shift", ModShift},
+	{"Win", ModWin},
+}
+
+var namedKeys = map[string]uint32{
+	"Space": 0x20, "Enter": 0x0D, "Tab": 0x09, "Escape": 0x1B,
+	"Backspace": 0x08, "Delete": 0x2E, "Insert": 0x2D,
+	"Home": 0x24, "End": 0x23, "PageUp": 0x21, "PageDown": 0x22,
+	"Left": 0x25, "Up": 0x26, "Right": 0x27, "Down": 0x28,
+}
+
+// Parse reads a "+"-separated combination, case-insensitively. At least one
+// of Ctrl, Alt or Win is required so hotkeys can't swallow ordinary typing.
+func Parse(s string) (Hotkey, error) {
+	var h Hotkey
+	parts := strings.Split(s, "+")
+	for _, p := range parts[:len(parts)-1] {
+		p = strings.TrimSpace(p)
+		if strings.EqualFold(p, "Control") {
+			p = "Ctrl"
+		}
+		found := false
+		for _, m := range modNames {
+			if strings.EqualFold(p, m.name) {
+				h.Mods |= m.mod
+				found = true
+			}
+		}
+		if !found {
+			return Hotkey{}, fmt.Errorf("unknown modifier %q in %q", p, s)
+		}
+	}
+	key, err := parseKey(strings.TrimSpace(parts[len(parts)-1]))
+	if err != nil {
+		return Hotkey{}, fmt.Errorf("%w in %q", err, s)
+	}
+	h.Key = key
+	if h.Mods&(ModCtrl|ModAlt|ModWin) == 0 {
+		return Hotkey{}, fmt.Errorf("%q needs Ctrl, Alt or Win", s)
+	}
+	return h, nil
+}
+
+func parseKey(k string) (uint32, error) {
+	if len(k) == 1 {
+		c := strings.ToUpper(k)[0]
+		if c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' {
+			return uint32(c), nil
+		}
+	}
+	if rest, ok := strings.CutPrefix(strings.ToUpper(k), "F"); ok {
+		if n, err := strconv.Atoi(rest); err == nil && n >= 1 && n <= 24 {
+			return 0x70 + uint32(n-1), nil
+		}
+	}
+	for name, vk := range namedKeys {
+		if strings.EqualFold(k, name) {
+			return vk, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown key %q", k)
+}
+
+// String formats h in the canonical form Parse accepts.
+func (h Hotkey) String() string {
+	var parts []string
+	for _, m := range modNames {
+		if h.Mods&m.mod != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	switch {
+	case h.Key >= 'A' && h.Key <= 'Z' || h.Key >= '0' && h.Key <= '9':
+		parts = append(parts, string(rune(h.Key)))
+	case h.Key >= 0x70 && h.Key <= 0x87:
+		parts = append(parts, fmt.Sprintf("F%d", h.Key-0x70+1))
+	default:
+		for name, vk := range namedKeys {
+			if vk == h.Key {
+				parts = append(parts, name)
+			}
+		}
+	}
+	return strings.Join(parts, "+")
+}
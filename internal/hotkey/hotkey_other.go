@@ -0,0 +1,18 @@
+//go:build !windows
+
+package hotkey
+
+// Manager is a no-op outside Windows.
+type Manager struct{}
+
+func New(onPress func(name string)) *Manager {
+	return &Manager{}
+}
+
+// Set fails for any non-empty set of bindings.
+func (m *Manager) Set(bindings map[string]Hotkey) error {
+	if len(bindings) > 0 {
+		return ErrUnsupported
+	}
+	return nil
+}
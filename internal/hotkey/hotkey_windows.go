@@ -0,0 +1,122 @@
+package hotkey
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	wmHotkey    = 0x0312
+	wmApp       = 0x8000
+	modNoRepeat = 0x4000
+)
+
+var (
+	user32                = syscall.NewLazyDLL("user32.dll")
+	kernel32              = syscall.NewLazyDLL("kernel32.dll")
+	procRegisterHotKey    = user32.NewProc("RegisterHotKey")
+	procUnregisterHotKey  = user32.NewProc("UnregisterHotKey")
+	procGetMessage        = user32.NewProc("GetMessageW")
+	procPostThreadMessage = user32.NewProc("PostThreadMessageW")
+	procPeekMessage       = user32.NewProc("PeekMessageW")
+	procGetCurrentThread  = kernel32.NewProc("GetCurrentThreadId")
+)
+
+type msg struct {
+	hwnd    uintptr
+	message uint32
+	wParam  uintptr
+	lParam  uintptr
+	time    uint32
+	pt      struct{ x, y int32 }
+}
+
+type setRequest struct {
+	bindings map[string]Hotkey
+	done     chan error
+}
+
+// Manager owns the registered hotkeys. Win32 delivers WM_HOTKEY to the
+// thread that registered the key, so registration and the message loop
+// both run on one locked OS thread.
+type Manager struct {
+	thread   uint32
+	requests chan setRequest
+	done     chan struct{} // closed when the message loop exits
+}
+
+// errStopped is returned by Set once the hotkey thread's message loop has
+// exited and nothing will service the request.
+var errStopped = errors.New("hotkey thread has stopped")
+
+// New starts the hotkey thread. onPress is called with the name of the
+// binding whose keys were pressed; it runs on the hotkey thread and must
+// not block.
+func New(onPress func(name string)) *Manager {
+	m := &Manager{requests: make(chan setRequest), done: make(chan struct{})}
+	ready := make(chan struct{})
+	go m.loop(onPress, ready)
+	<-ready
+	return m
+}
+
+func (m *Manager) loop(onPress func(string), ready chan struct{}) {
+	defer close(m.done)
+	runtime.LockOSThread()
+	tid, _, _ := procGetCurrentThread.Call()
+	m.thread = uint32(tid)
+	// Create the thread's message queue before anyone posts to it.
+	var mq msg
+	procPeekMessage.Call(uintptr(unsafe.Pointer(&mq)), 0, 0, 0, 0)
+	close(ready)
+
+	var names []string
+	for {
+		var mg msg
+		r, _, _ := procGetMessage.Call(uintptr(unsafe.Pointer(&mg)), 0, 0, 0)
+		if int32(r) <= 0 {
+			return
+		}
+		switch mg.message {
+		case wmHotkey:
+			if id := int(mg.wParam); id >= 1 && id <= len(names) {
+				onPress(names[id-1])
+			}
+		case wmApp:
+			req := <-m.requests
+			for id := range names {
+				procUnregisterHotKey.Call(0, uintptr(id+1))
+			}
+			names = names[:0]
+			var err error
+			for name, h := range req.bindings {
+				id := len(names) + 1
+				if r, _, e := procRegisterHotKey.Call(0, uintptr(id), uintptr(h.Mods|modNoRepeat), uintptr(h.Key)); r == 0 {
+					err = fmt.Errorf("%s is already used by another application: %w", h, e)
+					break
+				}
+				names = append(names, name)
+			}
+			req.done <- err
+		}
+	}
+}
+
+// Set replaces all registered hotkeys with bindings. If any key can't be
+// registered (usually because another application owns it) the error says
+// which, and the keys registered before it stay active.
+func (m *Manager) Set(bindings map[string]Hotkey) error {
+	req := setRequest{bindings: bindings, done: make(chan error, 1)}
+	if r, _, err := procPostThreadMessage.Call(uintptr(m.thread), wmApp, 0, 0); r == 0 {
+		return err
+	}
+	select {
+	case m.requests <- req:
+	case <-m.done:
+		return errStopped
+	}
+	return <-req.done
+}
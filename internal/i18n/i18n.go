@@ -0,0 +1,81 @@
+// Package i18n translates strings produced on the Go side (dialog titles,
+// file type names, notifications) using catalogs embedded in the binary.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// fallback is used for keys missing from the current locale's catalog.
+const fallback = "en"
+
+//go:embed locales/*.json
+var files embed.FS
+
+var (
+	catalogs = load()
+	current  atomic.Value // string
+)
+
+func load() map[string]map[string]string {
+	entries, err := files.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	out := make(map[string]map[string]string, len(entries))
+	for _, e := range entries {
+		data, err := files.ReadFile(path.Join("locales", e.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: bad catalog %s: %v", e.Name(), err))
+		}
+		out[strings.TrimSuffix(e.Name(), ".json")] = catalog
+	}
+	return out
+}
+
+// Locales lists the available locales.
+func Locales() []string {
+	var out []string
+	for l := range catalogs {
+		out = append(out, l)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// SetLocale switches the locale used by T.
+func SetLocale(locale string) error {
+	if _, ok := catalogs[locale]; !ok {
+		return fmt.Errorf("unsupported locale %q", locale)
+	}
+	current.Store(locale)
+	return nil
+}
+
+// T returns the translation of key in the current locale, formatted with
+// args if any. Unknown keys are returned as-is so a missing entry is
+// visible rather than blank.
+func T(key string, args ...any) string {
+	locale, _ := current.Load().(string)
+	msg, ok := catalogs[locale][key]
+	if !ok {
+		msg, ok = catalogs[fallback][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
@@ -14,9 +14,10 @@ import (
 )
 
 type Client struct {
-	address string
-	conn    net.Conn
-	mu      sync.Mutex
+	address    string
+	conn       net.Conn
+	mu         sync.Mutex
+	adminToken string
 }
 
 func NewClient() *Client {
@@ -63,13 +64,14 @@ func (c *Client) Request(method string, params interface{}) (json.RawMessage, er
 		return nil, err
 	}
 
+	c.mu.Lock()
 	req := Request{
-		ID:     id,
-		Method: method,
-		Params: paramsJSON,
+		ID:         id,
+		Method:     method,
+		Params:     paramsJSON,
+		AdminToken: c.adminToken,
 	}
 
-	c.mu.Lock()
 	encoder := json.NewEncoder(c.conn)
 	if err := encoder.Encode(req); err != nil {
 		c.conn.Close()
@@ -98,3 +100,11 @@ func (c *Client) Request(method string, params interface{}) (json.RawMessage, er
 
 	return resp.Result, nil
 }
+
+// SetAdminToken attaches token to every following request; an empty token
+// stops sending one.
+func (c *Client) SetAdminToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.adminToken = token
+}
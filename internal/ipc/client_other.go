@@ -20,3 +20,5 @@ func NewClient() *Client {
 func (c *Client) Request(method string, params any) (json.RawMessage, error) {
 	return nil, fmt.Errorf("IPC client is only supported on Windows")
 }
+
+func (c *Client) SetAdminToken(token string) {}
@@ -9,6 +9,9 @@ type Request struct {
 	ID     string          `json:"id"`
 	Method string          `json:"method"`
 	Params json.RawMessage `json:"params"`
+	// AdminToken proves a recent admin PIN verification; the server requires
+	// it on configuration-changing and destructive methods.
+	AdminToken string `json:"adminToken,omitempty"`
 }
 
 // Response is a message received from the server.
@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// bufferSize is how many recent records are kept in memory for the viewer.
+const bufferSize = 2000
+
+// Entry is one log record as shown in the diagnostics console.
+type Entry struct {
+	Time      time.Time         `json:"time"`
+	Level     string            `json:"level"`
+	Component string            `json:"component,omitempty"`
+	Message   string            `json:"message"`
+	Attrs     map[string]string `json:"attrs,omitempty"`
+}
+
+// ring holds the most recent entries and fans new ones out to subscribers.
+type ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	subs    []func(Entry)
+}
+
+var recent = &ring{}
+
+func (r *ring) add(e Entry) {
+	r.mu.Lock()
+	if len(r.entries) < bufferSize {
+		r.entries = append(r.entries, e)
+	} else {
+		r.entries[r.next] = e
+		r.next = (r.next + 1) % bufferSize
+	}
+	subs := slices.Clone(r.subs)
+	r.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(e)
+	}
+}
+
+// Subscribe registers fn to be called with every new entry. fn runs on the
+// logging goroutine and must not log itself.
+func Subscribe(fn func(Entry)) {
+	recent.mu.Lock()
+	defer recent.mu.Unlock()
+	recent.subs = append(recent.subs, fn)
+}
+
+// Recent returns up to limit buffered entries, oldest first, at or above
+// minLevel, newer than since and, if component is set, from that component.
+func Recent(minLevel string, component string, since time.Time, limit int) ([]Entry, error) {
+	var threshold slog.Level
+	if minLevel != "" {
+		if err := threshold.UnmarshalText([]byte(minLevel)); err != nil {
+			return nil, err
+		}
+	}
+
+	recent.mu.Lock()
+	ordered := append(slices.Clone(recent.entries[recent.next:]), recent.entries[:recent.next]...)
+	recent.mu.Unlock()
+
+	var out []Entry
+	for _, e := range ordered {
+		var l slog.Level
+		_ = l.UnmarshalText([]byte(e.Level))
+		if l < threshold || !e.Time.After(since) || (component != "" && e.Component != component) {
+			continue
+		}
+		out = append(out, e)
+	}
+	if limit > 0 && len(out) > limit {
+		out = out[len(out)-limit:]
+	}
+	return out, nil
+}
+
+// bufferHandler records every handled record into the ring before passing it
+// on to the wrapped handler.
+type bufferHandler struct {
+	inner slog.Handler
+	attrs []slog.Attr
+}
+
+func (h *bufferHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.inner.Enabled(ctx, l)
+}
+
+func (h *bufferHandler) Handle(ctx context.Context, r slog.Record) error {
+	e := Entry{Time: r.Time, Level: r.Level.String(), Message: r.Message}
+	collect := func(a slog.Attr) bool {
+		if a.Key == "component" {
+			e.Component = a.Value.String()
+			return true
+		}
+		if e.Attrs == nil {
+			e.Attrs = make(map[string]string)
+		}
+		e.Attrs[a.Key] = a.Value.String()
+		return true
+	}
+	for _, a := range h.attrs {
+		collect(a)
+	}
+	r.Attrs(collect)
+	recent.add(e)
+	return h.inner.Handle(ctx, r)
+}
+
+func (h *bufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &bufferHandler{inner: h.inner.WithAttrs(attrs), attrs: append(slices.Clone(h.attrs), attrs...)}
+}
+
+func (h *bufferHandler) WithGroup(name string) slog.Handler {
+	return &bufferHandler{inner: h.inner.WithGroup(name), attrs: h.attrs}
+}
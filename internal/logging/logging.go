@@ -0,0 +1,41 @@
+// Package logging configures the UI's structured logger. Every record carries
+// a component tag so UI, IPC and window events can be told apart in one file.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+var level = new(slog.LevelVar)
+
+// Setup installs a leveled slog handler writing to w as the default logger.
+// Output from the standard log package is routed through it as well, and
+// recent records are kept in memory for Recent and Subscribe.
+func Setup(w io.Writer) {
+	text := slog.NewTextHandler(w, &slog.HandlerOptions{Level: level})
+	slog.SetDefault(slog.New(&bufferHandler{inner: text}))
+}
+
+// Component returns a logger tagged with the given component name.
+func Component(name string) *slog.Logger {
+	return slog.Default().With("component", name)
+}
+
+// SetLevel changes the minimum level at runtime. It accepts debug, info, warn
+// and error.
+func SetLevel(name string) error {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.ToUpper(name))); err != nil {
+		return fmt.Errorf("unknown log level %q", name)
+	}
+	level.Set(l)
+	return nil
+}
+
+// Level reports the current minimum level in lower case.
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
@@ -0,0 +1,121 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"veda-anchor-ui/internal/clock"
+)
+
+// RotatingFile is an append-only log file that is rotated once it grows past
+// MaxBytes. Rotated files are named <name>.<timestamp> next to the original;
+// only the newest MaxBackups are kept and any older than MaxAge are removed.
+type RotatingFile struct {
+	Path       string
+	MaxBytes   int64
+	MaxBackups int
+	MaxAge     time.Duration
+	Clock      clock.Clock
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// OpenRotating opens (or creates) path for appending with the given limits.
+func OpenRotating(path string, maxBytes int64, maxBackups int, maxAge time.Duration) (*RotatingFile, error) {
+	r := &RotatingFile{Path: path, MaxBytes: maxBytes, MaxBackups: maxBackups, MaxAge: maxAge, Clock: clock.Real{}}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	r.prune()
+	return r, nil
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	r.file = f
+	r.size = info.Size()
+	return nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.MaxBytes > 0 && r.size+int64(len(p)) > r.MaxBytes && r.size > 0 {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// rotate must be called with r.mu held.
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+	backup := fmt.Sprintf("%s.%s", r.Path, r.Clock.Now().Format("20060102-150405.000"))
+	if err := os.Rename(r.Path, backup); err != nil {
+		// On Windows another handle (AV, the indexer) often blocks the
+		// rename. Keep appending to the current file and try again on the
+		// next write rather than losing file logging for good.
+		fmt.Fprintf(os.Stderr, "log rotation failed, continuing in %s: %v\n", r.Path, err)
+		return r.open()
+	}
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.prune()
+	return nil
+}
+
+// prune removes backups beyond MaxBackups or older than MaxAge. Errors are
+// ignored; a leftover backup is not worth failing a log write over.
+func (r *RotatingFile) prune() {
+	backups, _ := filepath.Glob(r.Path + ".*")
+	// The timestamp suffix sorts chronologically.
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	for i, b := range backups {
+		expired := false
+		if r.MaxAge > 0 {
+			if info, err := os.Stat(b); err == nil && r.Clock.Now().Sub(info.ModTime()) > r.MaxAge {
+				expired = true
+			}
+		}
+		if expired || (r.MaxBackups > 0 && i >= r.MaxBackups) {
+			_ = os.Remove(b)
+		}
+	}
+}
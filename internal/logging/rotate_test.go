@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"veda-anchor-ui/internal/clock"
+)
+
+func TestPruneMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ui.log")
+	now := time.Now()
+	backups := map[string]time.Duration{
+		path + ".20261001-000000.000": 20 * 24 * time.Hour,
+		path + ".20261014-000000.000": 2 * 24 * time.Hour,
+	}
+	for b, age := range backups {
+		if err := os.WriteFile(b, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(b, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := &RotatingFile{Path: path, MaxAge: 14 * 24 * time.Hour, Clock: clock.NewFake(now)}
+	r.prune()
+	if _, err := os.Stat(path + ".20261001-000000.000"); !os.IsNotExist(err) {
+		t.Errorf("expired backup kept")
+	}
+	if _, err := os.Stat(path + ".20261014-000000.000"); err != nil {
+		t.Errorf("recent backup removed: %v", err)
+	}
+
+	// A week later the second backup has expired too.
+	r.Clock = clock.NewFake(now.Add(14 * 24 * time.Hour))
+	r.prune()
+	if _, err := os.Stat(path + ".20261014-000000.000"); !os.IsNotExist(err) {
+		t.Errorf("backup expired by the clock moving on was kept")
+	}
+}
+
+func TestRotateRenameFailureKeepsWriting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ui.log")
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	r, err := OpenRotating(path, 10, 3, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	r.Clock = clock.NewFake(now)
+	// A non-empty directory under the backup name makes the rename fail.
+	backup := path + "." + now.Format("20060102-150405.000")
+	if err := os.MkdirAll(filepath.Join(backup, "busy"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"first line\n", "second line\n", "third line\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q) after failed rotation: %v", line, err)
+		}
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "first line\nsecond line\nthird line\n"; string(got) != want {
+		t.Errorf("log contains %q, want %q", got, want)
+	}
+}
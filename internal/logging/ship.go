@@ -0,0 +1,220 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"veda-anchor-ui/internal/supervisor"
+)
+
+const (
+	shipBatchSize     = 50
+	shipFlushInterval = 5 * time.Second
+	shipQueueSize     = 500
+	shipRetries       = 3
+)
+
+// ShipperConfig selects where WARN and above are forwarded. Kind is "syslog"
+// (Address is host:port, sent over UDP) or "http" (Address is an HTTPS URL
+// receiving JSON arrays of entries).
+type ShipperConfig struct {
+	Enabled bool   `json:"enabled"`
+	Kind    string `json:"kind"`
+	Address string `json:"address"`
+}
+
+// Shipper batches warning and error entries and forwards them to a remote
+// collector, retrying with backoff. Entries are dropped if the queue is full;
+// shipping must never block logging.
+type Shipper struct {
+	mu       sync.Mutex
+	cfg      ShipperConfig
+	queue    chan Entry
+	flushReq chan chan struct{}
+	client   *http.Client
+}
+
+// NewShipper creates a disabled shipper subscribed to the log stream.
+func NewShipper() *Shipper {
+	s := &Shipper{
+		queue:    make(chan Entry, shipQueueSize),
+		flushReq: make(chan chan struct{}),
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+	Subscribe(s.enqueue)
+	go supervisor.Run("log-shipper", s.run, nil)
+	return s
+}
+
+// Validate checks an enabled config: syslog needs host:port and http an
+// https:// URL, since entries can carry user and machine names.
+func (cfg ShipperConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	switch cfg.Kind {
+	case "syslog":
+		if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+			return fmt.Errorf("syslog address %q must be host:port", cfg.Address)
+		}
+	case "http":
+		u, err := url.Parse(cfg.Address)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("log collector %q must be an https:// URL", cfg.Address)
+		}
+	default:
+		return fmt.Errorf("unknown log shipping kind %q", cfg.Kind)
+	}
+	return nil
+}
+
+// Configure replaces the shipper's destination.
+func (s *Shipper) Configure(cfg ShipperConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cfg = cfg
+	return nil
+}
+
+func (s *Shipper) config() ShipperConfig {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cfg
+}
+
+func (s *Shipper) enqueue(e Entry) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(e.Level)); err != nil || l < slog.LevelWarn {
+		return
+	}
+	if !s.config().Enabled {
+		return
+	}
+	select {
+	case s.queue <- e:
+	default:
+	}
+}
+
+func (s *Shipper) run() {
+	ticker := time.NewTicker(shipFlushInterval)
+	defer ticker.Stop()
+	var batch []Entry
+	for {
+		select {
+		case e := <-s.queue:
+			batch = append(batch, e)
+			if len(batch) < shipBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case done := <-s.flushReq:
+			for len(s.queue) > 0 {
+				batch = append(batch, <-s.queue)
+			}
+			s.flush(batch)
+			batch = nil
+			close(done)
+			continue
+		}
+		s.flush(batch)
+		batch = nil
+	}
+}
+
+// Flush ships everything queued so far, giving up after timeout. It is
+// called on shutdown so the last warnings before exit aren't lost.
+func (s *Shipper) Flush(timeout time.Duration) {
+	done := make(chan struct{})
+	select {
+	case s.flushReq <- done:
+	case <-time.After(timeout):
+		return
+	}
+	select {
+	case <-done:
+	case <-time.After(timeout):
+	}
+}
+
+func (s *Shipper) flush(batch []Entry) {
+	cfg := s.config()
+	if !cfg.Enabled {
+		return
+	}
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err := s.send(cfg, batch)
+		if err == nil {
+			return
+		}
+		if attempt == shipRetries {
+			// Logging this would feed the failure back into the shipper.
+			fmt.Fprintf(os.Stderr, "log shipping failed, dropping %d entries: %v\n", len(batch), err)
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (s *Shipper) send(cfg ShipperConfig, batch []Entry) error {
+	switch cfg.Kind {
+	case "http":
+		body, err := json.Marshal(batch)
+		if err != nil {
+			return err
+		}
+		resp, err := s.client.Post(cfg.Address, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("collector returned %s", resp.Status)
+		}
+		return nil
+	case "syslog":
+		conn, err := net.DialTimeout("udp", cfg.Address, 5*time.Second)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = conn.Close() }()
+		host, _ := os.Hostname()
+		for _, e := range batch {
+			// RFC 5424, facility user (1); severity 3 for errors, 4 for warnings.
+			pri := 8 + 4
+			if e.Level == slog.LevelError.String() {
+				pri = 8 + 3
+			}
+			msg := fmt.Sprintf("<%d>1 %s %s veda-anchor-ui - %s - %s\n",
+				pri, e.Time.Format(time.RFC3339), host, orNil(e.Component), e.Message)
+			if _, err := conn.Write([]byte(msg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown log shipping kind %q", cfg.Kind)
+}
+
+func orNil(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
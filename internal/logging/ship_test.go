@@ -0,0 +1,24 @@
+package logging
+
+import "testing"
+
+func TestShipperConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg  ShipperConfig
+		ok   bool
+		name string
+	}{
+		{ShipperConfig{Enabled: false, Kind: "http", Address: "http://x"}, true, "disabled"},
+		{ShipperConfig{Enabled: true, Kind: "http", Address: "https://logs.example.com/in"}, true, "https"},
+		{ShipperConfig{Enabled: true, Kind: "http", Address: "http://logs.example.com/in"}, false, "plain http"},
+		{ShipperConfig{Enabled: true, Kind: "http", Address: "https://"}, false, "no host"},
+		{ShipperConfig{Enabled: true, Kind: "syslog", Address: "10.0.0.5:514"}, true, "syslog"},
+		{ShipperConfig{Enabled: true, Kind: "syslog", Address: "10.0.0.5"}, false, "syslog no port"},
+		{ShipperConfig{Enabled: true, Kind: "ftp", Address: "x"}, false, "unknown kind"},
+	}
+	for _, tt := range tests {
+		if err := tt.cfg.Validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: Validate() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
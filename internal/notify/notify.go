@@ -0,0 +1,19 @@
+// Package notify shows native desktop notifications: toasts on Windows,
+// Notification Center on macOS and libnotify on Linux. They appear whether
+// or not the window is open.
+package notify
+
+// appName is shown as the notification's source.
+const appName = "Veda Anchor"
+
+// Notification is a single message.
+type Notification struct {
+	Title string
+	Body  string
+}
+
+// Send shows n. It returns once the notification has been handed to the
+// OS, not when the user dismisses it.
+func Send(n Notification) error {
+	return send(n)
+}
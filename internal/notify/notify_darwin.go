@@ -0,0 +1,16 @@
+package notify
+
+import (
+	"os/exec"
+	"strings"
+)
+
+func send(n Notification) error {
+	script := "display notification " + quote(n.Body) + " with title " + quote(appName) + " subtitle " + quote(n.Title)
+	return exec.Command("osascript", "-e", script).Run()
+}
+
+// quote makes s an AppleScript string literal.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
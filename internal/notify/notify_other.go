@@ -0,0 +1,9 @@
+//go:build !windows && !darwin
+
+package notify
+
+import "os/exec"
+
+func send(n Notification) error {
+	return exec.Command("notify-send", "--app-name", appName, n.Title, n.Body).Run()
+}
@@ -0,0 +1,8 @@
+package notify
+
+import toast "git.sr.ht/~jackmordaunt/go-toast/v2"
+
+func send(n Notification) error {
+	t := toast.Notification{AppID: appName, Title: n.Title, Body: n.Body}
+	return t.Push()
+}
@@ -0,0 +1,190 @@
+// Package settings persists the UI's own preferences — things that must be
+// known before (or without) reaching the agent — as a JSON file with typed
+// fields, defaults and validation.
+package settings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"veda-anchor-ui/internal/hotkey"
+	"veda-anchor-ui/internal/i18n"
+)
+
+// Settings are the UI-side preferences. Agent behaviour (intervals,
+// retention, blocking modes) is stored by the agent, not here.
+type Settings struct {
+	LogLevel string `json:"logLevel"`
+	// Locale selects the language of Go-generated strings and is passed on
+	// to the agent for notifications and block pages.
+	Locale string `json:"locale"`
+	// CloseAction is what the window's X button does: "hide" to the tray,
+	// "minimize", or "quit" the UI and stop the agent.
+	CloseAction string `json:"closeAction"`
+	// LaunchState is how the window first appears: "normal", "hidden" (tray
+	// only) or "minimized". The --hidden and --minimized flags override it.
+	LaunchState string `json:"launchState"`
+	// UpdateChannel is "stable", "beta" (includes pre-releases) or "off".
+	UpdateChannel string `json:"updateChannel"`
+	// CrashReporting opts in to uploading crash reports. Reports are always
+	// written locally so they can be reviewed first.
+	CrashReporting bool `json:"crashReporting"`
+	// Hotkeys maps a HotkeyActions entry to a global key combination such as
+	// "Ctrl+Alt+A"; missing or empty entries are unbound.
+	Hotkeys map[string]string `json:"hotkeys"`
+	// Notifications enables native notifications per category in
+	// NotificationCategories; a missing category is enabled.
+	Notifications map[string]bool `json:"notifications"`
+}
+
+// NotificationCategories are the kinds of native notification that can be
+// turned off individually.
+var NotificationCategories = []string{"limit", "block", "goal", "break", "tamper"}
+
+// HotkeyActions are the actions a global hotkey can trigger.
+var HotkeyActions = []string{"toggleWindow", "togglePrivacy", "startFocus"}
+
+// Defaults returns the settings used for anything missing from the file.
+func Defaults() Settings {
+	return Settings{
+		LogLevel:      "info",
+		Locale:        "vi",
+		CloseAction:   "hide",
+		LaunchState:   "normal",
+		UpdateChannel: "stable",
+		Hotkeys:       map[string]string{"toggleWindow": "Ctrl+Alt+A"},
+	}
+}
+
+// Validate reports the first invalid field.
+func (s Settings) Validate() error {
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, s.LogLevel) {
+		return fmt.Errorf("unknown log level %q", s.LogLevel)
+	}
+	if !slices.Contains(i18n.Locales(), s.Locale) {
+		return fmt.Errorf("unsupported locale %q", s.Locale)
+	}
+	if !slices.Contains([]string{"hide", "minimize", "quit"}, s.CloseAction) {
+		return fmt.Errorf("unknown close action %q", s.CloseAction)
+	}
+	if !slices.Contains([]string{"normal", "hidden", "minimized"}, s.LaunchState) {
+		return fmt.Errorf("unknown launch state %q", s.LaunchState)
+	}
+	if !slices.Contains([]string{"stable", "beta", "off"}, s.UpdateChannel) {
+		return fmt.Errorf("unknown update channel %q", s.UpdateChannel)
+	}
+	seen := map[hotkey.Hotkey]string{}
+	for action, combo := range s.Hotkeys {
+		if !slices.Contains(HotkeyActions, action) {
+			return fmt.Errorf("unknown hotkey action %q", action)
+		}
+		if combo == "" {
+			continue
+		}
+		h, err := hotkey.Parse(combo)
+		if err != nil {
+			return err
+		}
+		if other, dup := seen[h]; dup {
+			return fmt.Errorf("%s is bound to both %s and %s", h, other, action)
+		}
+		seen[h] = action
+	}
+	for category := range s.Notifications {
+		if !slices.Contains(NotificationCategories, category) {
+			return fmt.Errorf("unknown notification category %q", category)
+		}
+	}
+	return nil
+}
+
+// Store holds the current settings and writes every change back to disk.
+type Store struct {
+	path string
+	mu   sync.Mutex
+	cur  Settings
+	subs []func(old, new Settings)
+}
+
+// Open loads settings from path. A missing file yields the defaults; a
+// corrupt or invalid one is reported but the store is still usable with
+// defaults so the UI can start.
+func Open(path string) (*Store, error) {
+	s := &Store{path: path, cur: Defaults()}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	loaded := Defaults()
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		return s, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if err := loaded.Validate(); err != nil {
+		return s, fmt.Errorf("invalid settings in %s: %w", path, err)
+	}
+	s.cur = loaded
+	return s, nil
+}
+
+// Get returns a copy of the current settings.
+func (s *Store) Get() Settings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cur
+}
+
+// Update applies fn to a copy of the settings, validates and persists the
+// result, then notifies subscribers. Nothing changes if any step fails.
+func (s *Store) Update(fn func(*Settings)) error {
+	s.mu.Lock()
+	old := s.cur
+	next := old
+	fn(&next)
+	if err := next.Validate(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	if err := s.save(next); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	s.cur = next
+	subs := slices.Clone(s.subs)
+	s.mu.Unlock()
+
+	for _, fn := range subs {
+		fn(old, next)
+	}
+	return nil
+}
+
+// Subscribe registers fn to be called after every successful Update.
+func (s *Store) Subscribe(fn func(old, new Settings)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subs = append(s.subs, fn)
+}
+
+// save writes through a temp file so a crash never leaves half a file.
+func (s *Store) save(v Settings) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
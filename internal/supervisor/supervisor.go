@@ -0,0 +1,51 @@
+// Package supervisor keeps long-running background goroutines alive: a panic
+// is logged with its stack trace and the goroutine is restarted with backoff
+// instead of silently dying until the next launch.
+package supervisor
+
+import (
+	"log/slog"
+	"runtime/debug"
+	"time"
+)
+
+const (
+	initialBackoff = time.Second
+	maxBackoff     = time.Minute
+	// A run that lasts this long without panicking resets the backoff.
+	stableAfter = 5 * time.Minute
+)
+
+// Run calls fn until it returns normally. Every time fn panics, onPanic (if
+// non-nil) is told the name, recovered value and stack, and fn is restarted
+// after a backoff that doubles up to a minute.
+func Run(name string, fn func(), onPanic func(name string, recovered any, stack []byte)) {
+	logger := slog.Default().With("component", "supervisor", "monitor", name)
+	backoff := initialBackoff
+	for {
+		started := time.Now()
+		recovered, stack := call(fn)
+		if recovered == nil {
+			return
+		}
+		logger.Error("Monitor panicked, restarting", "panic", recovered, "backoff", backoff, "stack", string(stack))
+		if onPanic != nil {
+			onPanic(name, recovered, stack)
+		}
+		if time.Since(started) > stableAfter {
+			backoff = initialBackoff
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+func call(fn func()) (recovered any, stack []byte) {
+	defer func() {
+		if r := recover(); r != nil {
+			recovered, stack = r, debug.Stack()
+		}
+	}()
+	fn()
+	return nil, nil
+}
@@ -0,0 +1,108 @@
+// Package throttle protects the agent from floods of expensive requests: a
+// per-method token bucket caps the call rate, and identical concurrent calls
+// are collapsed into one (single-flight).
+package throttle
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"veda-anchor-ui/internal/clock"
+)
+
+// ErrRateLimited is returned when a method is called faster than its limit.
+var ErrRateLimited = errors.New("too many requests, try again shortly")
+
+// errCallPanicked is what callers sharing a call see if it panicked.
+var errCallPanicked = errors.New("shared call panicked")
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type call struct {
+	done chan struct{}
+	val  any
+	err  error
+}
+
+// Limiter allows Rate calls per second per method with bursts up to Burst.
+type Limiter struct {
+	Rate  float64
+	Burst float64
+	Clock clock.Clock
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   map[string]*call
+}
+
+// New returns a Limiter with the given per-method rate and burst.
+func New(rate, burst float64) *Limiter {
+	return &Limiter{
+		Rate:    rate,
+		Burst:   burst,
+		Clock:   clock.Real{},
+		buckets: make(map[string]*bucket),
+		calls:   make(map[string]*call),
+	}
+}
+
+// Do runs fn for method unless an identical call (same key) is already in
+// flight, in which case it waits for and shares that call's result. Only
+// calls that actually run consume a token.
+func (l *Limiter) Do(method, key string, fn func() (any, error)) (any, error) {
+	l.mu.Lock()
+	if c, ok := l.calls[key]; ok {
+		l.mu.Unlock()
+		<-c.done
+		return c.val, c.err
+	}
+
+	now := l.Clock.Now()
+	b, ok := l.buckets[method]
+	if !ok {
+		b = &bucket{tokens: l.Burst, last: now}
+		l.buckets[method] = b
+	}
+	b.tokens = min(l.Burst, b.tokens+now.Sub(b.last).Seconds()*l.Rate)
+	b.last = now
+	if b.tokens < 1 {
+		l.mu.Unlock()
+		return nil, ErrRateLimited
+	}
+	b.tokens--
+	return l.run(key, fn)
+}
+
+// Share is Do without the rate limit, for calls that are cheap on their own
+// but often requested in bulk or repeated while a first request is pending.
+func (l *Limiter) Share(key string, fn func() (any, error)) (any, error) {
+	l.mu.Lock()
+	if c, ok := l.calls[key]; ok {
+		l.mu.Unlock()
+		<-c.done
+		return c.val, c.err
+	}
+	return l.run(key, fn)
+}
+
+// run registers and runs a new call for key; it must be called with l.mu
+// held and releases it. If fn panics, waiters get errCallPanicked and the
+// panic continues in the caller.
+func (l *Limiter) run(key string, fn func() (any, error)) (any, error) {
+	c := &call{done: make(chan struct{}), err: errCallPanicked}
+	l.calls[key] = c
+	l.mu.Unlock()
+
+	defer func() {
+		l.mu.Lock()
+		delete(l.calls, key)
+		l.mu.Unlock()
+		close(c.done)
+	}()
+	c.val, c.err = fn()
+	return c.val, c.err
+}
@@ -0,0 +1,66 @@
+package throttle
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"veda-anchor-ui/internal/clock"
+)
+
+func TestLimiterRefill(t *testing.T) {
+	f := clock.NewFake(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+	l := New(1, 2)
+	l.Clock = f
+	call := func() error {
+		_, err := l.Do("m", "k", func() (any, error) { return nil, nil })
+		return err
+	}
+	for i := range 2 {
+		if err := call(); err != nil {
+			t.Fatalf("call %d within burst: %v", i, err)
+		}
+	}
+	if err := call(); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("call past burst = %v, want ErrRateLimited", err)
+	}
+	f.Advance(time.Second)
+	if err := call(); err != nil {
+		t.Fatalf("call after refill: %v", err)
+	}
+}
+
+func TestShareIsNotRateLimited(t *testing.T) {
+	l := New(1, 1)
+	l.Clock = clock.NewFake(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC))
+	for i := range 100 {
+		if _, err := l.Share("k", func() (any, error) { return nil, nil }); err != nil {
+			t.Fatalf("shared call %d: %v", i, err)
+		}
+	}
+}
+
+func TestPanickingCallReleasesKey(t *testing.T) {
+	l := New(1, 1)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("panic was swallowed")
+			}
+		}()
+		_, _ = l.Share("k", func() (any, error) { panic("boom") })
+	}()
+	done := make(chan error, 1)
+	go func() {
+		_, err := l.Share("k", func() (any, error) { return nil, nil })
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("call after panic: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("call after panic blocked on the dead call")
+	}
+}
@@ -0,0 +1,298 @@
+// Package update finds, downloads and verifies new UI builds published as
+// GitHub releases. Stable follows full releases only; beta also follows
+// pre-releases. Every installer must ship with a <installer>.sha256 asset
+// and a <installer>.sig asset: the base64 Ed25519 signature of the
+// installer's SHA-256 digest, made with the release key whose public half
+// is built into the UI (PublicKey).
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const releasesURL = "https://api.github.com/repos/VedaIO/veda-anchor-ui/releases"
+
+// PublicKey is the base64 Ed25519 release signing key, set at build time via
+// -ldflags "-X veda-anchor-ui/internal/update.PublicKey=...". Builds without
+// it can find updates but refuse to download them.
+var PublicKey string
+
+// ErrNoPublicKey is returned by Download and Verify in builds without a
+// release signing key.
+var ErrNoPublicKey = errors.New("this build has no update signing key")
+
+// Release is an installable update.
+type Release struct {
+	Version      string `json:"version"`
+	Notes        string `json:"notes"`
+	Prerelease   bool   `json:"prerelease"`
+	InstallerURL string `json:"-"`
+	ChecksumURL  string `json:"-"`
+	SignatureURL string `json:"-"`
+	Installer    string `json:"installer"`
+	// Signature is filled in by Download and checked again by Verify.
+	Signature []byte `json:"-"`
+}
+
+type ghRelease struct {
+	TagName    string `json:"tag_name"`
+	Body       string `json:"body"`
+	Draft      bool   `json:"draft"`
+	Prerelease bool   `json:"prerelease"`
+	Assets     []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Latest returns the newest release on channel ("stable" or "beta") that
+// has an installer for arch with a checksum and signature, or nil if none
+// does.
+func Latest(ctx context.Context, client *http.Client, channel, arch string) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releasesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("release feed returned %s", resp.Status)
+	}
+	var releases []ghRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, err
+	}
+
+	var best *Release
+	for _, r := range releases {
+		if r.Draft || (r.Prerelease && channel != "beta") {
+			continue
+		}
+		rel := &Release{Version: strings.TrimPrefix(r.TagName, "v"), Notes: r.Body, Prerelease: r.Prerelease}
+		suffix := "-" + arch + "-installer.exe"
+		for _, a := range r.Assets {
+			if strings.HasSuffix(a.Name, suffix) {
+				rel.Installer, rel.InstallerURL = a.Name, a.URL
+			}
+		}
+		for _, a := range r.Assets {
+			switch {
+			case rel.Installer == "":
+			case a.Name == rel.Installer+".sha256":
+				rel.ChecksumURL = a.URL
+			case a.Name == rel.Installer+".sig":
+				rel.SignatureURL = a.URL
+			}
+		}
+		if rel.InstallerURL == "" || rel.ChecksumURL == "" || rel.SignatureURL == "" {
+			continue
+		}
+		if best == nil || Newer(best.Version, rel.Version) {
+			best = rel
+		}
+	}
+	return best, nil
+}
+
+// Newer reports whether candidate is a later version than current. Versions
+// are dotted numbers with an optional "-suffix"; a suffixed version sorts
+// before the same version without one. Non-release builds ("dev", git
+// describe output) never consider anything newer.
+func Newer(current, candidate string) bool {
+	cur, curPre, ok := parse(current)
+	if !ok {
+		return false
+	}
+	cand, candPre, ok := parse(candidate)
+	if !ok {
+		return false
+	}
+	for i := range max(len(cur), len(cand)) {
+		var c, n int
+		if i < len(cur) {
+			c = cur[i]
+		}
+		if i < len(cand) {
+			n = cand[i]
+		}
+		if n != c {
+			return n > c
+		}
+	}
+	if curPre != "" && candPre == "" {
+		return true
+	}
+	return curPre != "" && candPre != "" && candPre > curPre
+}
+
+func parse(v string) (nums []int, pre string, ok bool) {
+	v = strings.TrimPrefix(v, "v")
+	v, pre, _ = strings.Cut(v, "-")
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, "", false
+		}
+		nums = append(nums, n)
+	}
+	return nums, pre, true
+}
+
+// Download fetches the installer into dir and verifies it against the
+// published SHA-256 and the release signature. A file that fails
+// verification is deleted.
+func Download(ctx context.Context, client *http.Client, rel *Release, dir string) (string, error) {
+	key, err := publicKey()
+	if err != nil {
+		return "", err
+	}
+	sum, err := fetchChecksum(ctx, client, rel.ChecksumURL)
+	if err != nil {
+		return "", err
+	}
+	sig, err := fetchSignature(ctx, client, rel.SignatureURL)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, rel.Installer)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rel.InstallerURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("installer download returned %s", resp.Status)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, h), resp.Body)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	digest := h.Sum(nil)
+	if err == nil && hex.EncodeToString(digest) != sum {
+		err = fmt.Errorf("checksum mismatch for %s", rel.Installer)
+	}
+	if err == nil && !ed25519.Verify(key, digest, sig) {
+		err = fmt.Errorf("invalid signature for %s", rel.Installer)
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+	rel.Signature = sig
+	return path, nil
+}
+
+// Verify re-checks a downloaded installer against the signature Download
+// accepted, right before it is run, in case the file changed on disk since.
+func Verify(path string, rel *Release) error {
+	if rel == nil || len(rel.Signature) == 0 {
+		return fmt.Errorf("%s was not downloaded through Download", path)
+	}
+	key, err := publicKey()
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, h.Sum(nil), rel.Signature) {
+		return fmt.Errorf("invalid signature for %s", path)
+	}
+	return nil
+}
+
+func publicKey() (ed25519.PublicKey, error) {
+	if PublicKey == "" {
+		return nil, ErrNoPublicKey
+	}
+	key, err := base64.StdEncoding.DecodeString(PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("malformed update signing key")
+	}
+	return ed25519.PublicKey(key), nil
+}
+
+// fetchSignature reads a base64 Ed25519 signature file.
+func fetchSignature(ctx context.Context, client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("signature download returned %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return nil, err
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return nil, fmt.Errorf("malformed signature file")
+	}
+	return sig, nil
+}
+
+// fetchChecksum reads a sha256sum-style file ("<hex>  <name>" or just hex).
+func fetchChecksum(ctx context.Context, client *http.Client, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("checksum download returned %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+		return "", fmt.Errorf("malformed checksum file")
+	}
+	return strings.ToLower(fields[0]), nil
+}
@@ -0,0 +1,80 @@
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestDownloadVerifiesSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherPriv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	PublicKey = base64.StdEncoding.EncodeToString(pub)
+	defer func() { PublicKey = "" }()
+
+	installer := []byte("installer bytes")
+	digest := sha256.Sum256(installer)
+	sigs := map[string][]byte{
+		"good":   ed25519.Sign(priv, digest[:]),
+		"forged": ed25519.Sign(otherPriv, digest[:]),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/setup.exe":
+			_, _ = w.Write(installer)
+		case "/setup.exe.sha256":
+			_, _ = w.Write([]byte(hex.EncodeToString(digest[:]) + "  setup.exe\n"))
+		default:
+			_, _ = w.Write([]byte(base64.StdEncoding.EncodeToString(sigs[r.URL.Path[1:]])))
+		}
+	}))
+	defer srv.Close()
+
+	for name, wantOK := range map[string]bool{"good": true, "forged": false} {
+		t.Run(name, func(t *testing.T) {
+			rel := &Release{
+				Installer:    "setup.exe",
+				InstallerURL: srv.URL + "/setup.exe",
+				ChecksumURL:  srv.URL + "/setup.exe.sha256",
+				SignatureURL: srv.URL + "/" + name,
+			}
+			path, err := Download(context.Background(), srv.Client(), rel, t.TempDir())
+			if (err == nil) != wantOK {
+				t.Fatalf("Download() error = %v, want ok=%v", err, wantOK)
+			}
+			if !wantOK {
+				return
+			}
+			if err := Verify(path, rel); err != nil {
+				t.Fatalf("Verify() after download: %v", err)
+			}
+			// A file swapped after download must not pass.
+			if err := os.WriteFile(path, []byte("tampered"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := Verify(path, rel); err == nil {
+				t.Fatal("Verify() accepted a modified installer")
+			}
+		})
+	}
+}
+
+func TestDownloadWithoutPublicKey(t *testing.T) {
+	PublicKey = ""
+	if _, err := Download(context.Background(), http.DefaultClient, &Release{}, t.TempDir()); !errors.Is(err, ErrNoPublicKey) {
+		t.Fatalf("Download() error = %v, want ErrNoPublicKey", err)
+	}
+}
@@ -7,9 +7,17 @@ import (
 	"github.com/wailsapp/wails/v2/pkg/options"
 	"github.com/wailsapp/wails/v2/pkg/options/assetserver"
 	"github.com/wailsapp/wails/v2/pkg/options/windows"
-	"log"
 	"os"
 	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"time"
+	_ "time/tzdata" // Windows has no zoneinfo database for time.LoadLocation
+
+	"veda-anchor-ui/internal/ipc"
+	"veda-anchor-ui/internal/logging"
+	"veda-anchor-ui/internal/supervisor"
 )
 
 // Embed the entire frontend/dist directory into the Go binary
@@ -17,42 +25,180 @@ import (
 //go:embed all:frontend/dist
 var assets embed.FS
 
+// version is set at build time via -ldflags "-X main.version=...".
+var version = "dev"
+
+// shutdownFlushTimeout bounds how long shutdown waits for queued log
+// entries to be shipped.
+const shutdownFlushTimeout = 3 * time.Second
+
+// uiLogName is the UI's log file inside logDir; rotated copies get a suffix.
+const uiLogName = "veda-anchor_ui.log"
+
+// dataDir is the machine-wide folder shared with the agent.
+func dataDir() string {
+	progData := os.Getenv("ProgramData")
+	if progData == "" {
+		progData = `C:\ProgramData`
+	}
+	return filepath.Join(progData, "VedaAnchor")
+}
+
+func logDir() string {
+	return filepath.Join(dataDir(), "logs")
+}
+
+// isNativeMessagingLaunch recognises a browser starting us as a native
+// messaging host: either the --native-messaging flag our manifests pass, or
+// the caller's origin, which Chromium-based browsers always give as the
+// first argument.
+func isNativeMessagingLaunch(args []string) bool {
+	if slices.Contains(args, "--native-messaging") {
+		return true
+	}
+	return len(args) > 0 && strings.HasPrefix(args[0], "chrome-extension://")
+}
+
 func (a *App) startup(ctx context.Context) {
 	a.ctx = ctx
+	a.applySettings()
+	a.streamLogs()
+	go a.loadLogShippingConfig()
+	go a.loadAdminPinState()
+	go a.checkDatabaseRecovery()
+	go supervisor.Run("storage", a.watchStorage, a.reportMonitorPanic)
+	go supervisor.Run("goals", a.watchGoals, a.reportMonitorPanic)
+	go supervisor.Run("events", a.pumpEvents, a.reportMonitorPanic)
+	go a.runTray()
+	a.startHotkeys()
+	if cmds, err := parseInstanceCommands(os.Args[1:]); err != nil {
+		logging.Component("app").Warn("Ignoring command line actions", "error", err)
+	} else {
+		go a.runInstanceCommands(cmds)
+	}
+	go supervisor.Run("update", a.watchUpdates, a.reportMonitorPanic)
+	go supervisor.Run("clock", a.watchClock, a.reportMonitorPanic)
+	go a.uploadCrashReports()
+}
+
+// shutdown runs after the window has closed, on Quit and when Windows ends
+// the session. It stops the background monitors and flushes queued log
+// entries; the agent closes its own open intervals and drains its writer
+// when it is stopped. A downloaded update is installed now rather than
+// making the user wait for it at next launch.
+func (a *App) shutdown(ctx context.Context) {
+	a.stopOnce.Do(func() { close(a.stop) })
+	a.shipper.Flush(shutdownFlushTimeout)
+	if err := a.launchInstaller(); err == nil {
+		logging.Component("update").Info("Installing downloaded update on exit")
+		return
+	}
+	if a.restarting.Load() {
+		if err := relaunch(); err != nil {
+			logging.Component("app").Error("Failed to restart UI", "error", err)
+		}
+	}
 }
 
 func main() {
 	// CRITICAL: Log startup for debugging
-	progData := os.Getenv("ProgramData")
-	if progData == "" {
-		progData = `C:\ProgramData`
-	}
-	logDir := filepath.Join(progData, "VedaAnchor", "logs")
-	_ = os.MkdirAll(logDir, 0755)
+	_ = os.MkdirAll(logDir(), 0755)
 
-	logPath := filepath.Join(logDir, "veda-anchor_ui.log")
-	logFile, _ := os.OpenFile(logPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	logPath := filepath.Join(logDir(), uiLogName)
+	logFile, _ := logging.OpenRotating(logPath, 5<<20, 3, 14*24*time.Hour)
 	if logFile != nil {
 		defer func() { _ = logFile.Close() }()
-		log.SetOutput(logFile)
+		logging.Setup(logFile)
+	} else {
+		logging.Setup(os.Stderr)
+	}
+
+	logger := logging.Component("app")
+	logger.Info("=== ANCHOR UI LAUNCHED ===", "args", os.Args)
+
+	// The native messaging host is part of the agent. If a browser launches
+	// us through an outdated manifest, exit instead of opening a window.
+	if isNativeMessagingLaunch(os.Args[1:]) {
+		logger.Warn("Launched as a native messaging host; ignoring", "args", os.Args[1:])
+		return
+	}
+
+	// The uninstaller runs us with --purge-data when the user chose not to
+	// keep their history; ask the agent to wipe it and exit without a window.
+	if slices.Contains(os.Args[1:], "--purge-data") {
+		if _, err := ipc.NewClient().Request("PurgeData", nil); err != nil {
+			logger.Error("Failed to purge data", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if code, ok := runElevatedTask(os.Args[1:]); ok {
+		os.Exit(code)
+	}
+
+	// Headless subcommands (stats, export, block) run without a window.
+	if code, ok := runCLI(os.Args[1:]); ok {
+		os.Exit(code)
 	}
 
-	log.Printf("=== ANCHOR UI LAUNCHED === Args: %v", os.Args)
+	// A restart hands over to us while the previous instance still holds the
+	// single-instance lock; wait for it to exit first.
+	waitForPreviousInstance(os.Args[1:])
 
 	app := NewApp()
 
+	if raw, ok := findDeepLink(os.Args[1:]); ok {
+		link, err := parseDeepLink(raw)
+		if err != nil {
+			logger.Warn("Ignoring deep link", "link", raw, "error", err)
+		}
+		pendingDeepLink.link = link
+	}
+
+	// Record a report for a panic on the main goroutine, then let it crash
+	// the process as usual.
+	defer func() {
+		if r := recover(); r != nil {
+			app.recordCrash("main", r, debug.Stack(), true)
+			panic(r)
+		}
+	}()
+
+	// Autostart launches with --hidden (autostartArgs) so the window doesn't
+	// pop up over the desktop at login; the tray icon brings it back. Without
+	// a tray, hidden would leave no way back, so minimise instead.
+	launchState := app.settings.Get().LaunchState
+	switch {
+	case slices.Contains(os.Args[1:], "--hidden"):
+		launchState = "hidden"
+	case slices.Contains(os.Args[1:], "--minimized"):
+		launchState = "minimized"
+	}
+	if launchState == "hidden" && !trayAvailable {
+		launchState = "minimized"
+	}
+	app.hidden.Store(launchState == "hidden")
+	startState := options.Normal
+	if launchState == "minimized" {
+		startState = options.Minimised
+	}
+
 	// Create and run the Wails application
 	err := wails.Run(&options.App{
-		Title:       "VedaAnchor",
-		Width:       1024,
-		Height:      768,
-		Frameless:   true,
-		StartHidden: false,
+		Title:            "VedaAnchor",
+		Width:            1024,
+		Height:           768,
+		Frameless:        true,
+		StartHidden:      launchState == "hidden",
+		WindowStartState: startState,
 		AssetServer: &assetserver.Options{
 			Assets: assets,
 		},
 		BackgroundColour: &options.RGBA{R: 27, G: 38, B: 54, A: 1},
 		OnStartup:        app.startup,
+		OnBeforeClose:    app.beforeClose,
+		OnShutdown:       app.shutdown,
 
 		// Windows platform specific options
 		Windows: &windows.Options{
@@ -64,11 +210,8 @@ func main() {
 
 		// SingleInstanceLock: Ensure only one GUI instance runs
 		SingleInstanceLock: &options.SingleInstanceLock{
-			UniqueId: "com.vedaio.veda-anchor-ui",
-			OnSecondInstanceLaunch: func(data options.SecondInstanceData) {
-				log.Println("Second GUI instance detected - showing existing window")
-				app.ShowWindow()
-			},
+			UniqueId:               "com.vedaio.veda-anchor-ui",
+			OnSecondInstanceLaunch: app.onSecondInstance,
 		},
 
 		Bind: []any{
@@ -77,6 +220,7 @@ func main() {
 	})
 
 	if err != nil {
-		log.Fatal("Error running Wails app:", err)
+		logger.Error("Error running Wails app", "error", err)
+		os.Exit(1)
 	}
 }
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"veda-anchor-ui/internal/i18n"
+	"veda-anchor-ui/internal/logging"
+	"veda-anchor-ui/internal/notify"
+	"veda-anchor-ui/internal/settings"
+)
+
+// notifyEvents maps agent bus events to the notification they raise. When
+// withTarget is set the message takes the app name or domain from the
+// event data, falling back to the key's ".bodyGeneric" text without one.
+var notifyEvents = map[string]struct {
+	category, key string
+	withTarget    bool
+}{
+	"app_blocked":     {"block", "notify.appBlocked", true},
+	"web_blocked":     {"block", "notify.webBlocked", true},
+	"limit_reached":   {"limit", "notify.limitReached", true},
+	"break_due":       {"break", "notify.breakDue", false},
+	"tamper_detected": {"tamper", "notify.tamper", false},
+}
+
+// notify shows a native notification unless its category is turned off.
+func (a *App) notify(category, title, body string) {
+	if enabled, ok := a.settings.Get().Notifications[category]; ok && !enabled {
+		return
+	}
+	if err := notify.Send(notify.Notification{Title: title, Body: body}); err != nil {
+		logging.Component("notify").Warn("Failed to show notification", "category", category, "error", err)
+	}
+}
+
+// notifyEvent raises the notification for a bus event, if it has one.
+func (a *App) notifyEvent(e activityEvent) {
+	n, ok := notifyEvents[e.Type]
+	if !ok {
+		return
+	}
+	var data struct {
+		Name   string `json:"name"`
+		Domain string `json:"domain"`
+	}
+	_ = json.Unmarshal(e.Data, &data)
+	target := data.Name
+	if target == "" {
+		target = data.Domain
+	}
+	body := i18n.T(n.key + ".body")
+	if n.withTarget {
+		if target != "" {
+			body = i18n.T(n.key+".body", target)
+		} else {
+			body = i18n.T(n.key + ".bodyGeneric")
+		}
+	}
+	a.notify(n.category, i18n.T(n.key+".title"), body)
+}
+
+// GetNotificationSettings returns whether each category is enabled.
+func (a *App) GetNotificationSettings() map[string]bool {
+	cur := a.settings.Get().Notifications
+	out := make(map[string]bool, len(settings.NotificationCategories))
+	for _, c := range settings.NotificationCategories {
+		enabled, ok := cur[c]
+		out[c] = !ok || enabled
+	}
+	return out
+}
+
+// SetNotificationEnabled turns one category of notifications on or off.
+func (a *App) SetNotificationEnabled(category string, enabled bool) error {
+	if !slices.Contains(settings.NotificationCategories, category) {
+		return fmt.Errorf("unknown notification category %q", category)
+	}
+	if category == "tamper" && !enabled {
+		if err := a.requireAdmin(); err != nil {
+			return err
+		}
+	}
+	return a.settings.Update(func(s *settings.Settings) {
+		next := maps.Clone(s.Notifications)
+		if next == nil {
+			next = map[string]bool{}
+		}
+		next[category] = enabled
+		s.Notifications = next
+	})
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// restartWaitTimeout bounds how long a restarted instance waits for the old
+// one to exit before starting anyway.
+const restartWaitTimeout = 10 * time.Second
+
+// relaunch starts a new UI instance that waits for this process to exit.
+func relaunch() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	return exec.Command(exe, "--wait-pid="+strconv.Itoa(os.Getpid())).Start()
+}
+
+// waitForPreviousInstance blocks until the process named by --wait-pid has
+// exited, or restartWaitTimeout passes.
+func waitForPreviousInstance(args []string) {
+	for _, arg := range args {
+		v, ok := strings.CutPrefix(arg, "--wait-pid=")
+		if !ok {
+			continue
+		}
+		pid, err := strconv.Atoi(v)
+		if err != nil {
+			return
+		}
+		p, err := os.FindProcess(pid)
+		if err != nil {
+			return
+		}
+		exited := make(chan struct{})
+		go func() {
+			// Waiting on a process that isn't our child works on Windows;
+			// elsewhere it fails at once and the grace period below applies.
+			if _, err := p.Wait(); err != nil {
+				time.Sleep(time.Second)
+			}
+			close(exited)
+		}()
+		select {
+		case <-exited:
+		case <-time.After(restartWaitTimeout):
+		}
+		return
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func mustLoad(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return loc
+}
+
+func TestScheduleOccurrences(t *testing.T) {
+	ny := mustLoad(t, "America/New_York")
+	tests := []struct {
+		name      string
+		window    ScheduleWindow
+		from      time.Time
+		days      int
+		wantStart []time.Time
+		wantLen   []time.Duration
+	}{
+		{
+			name:      "spring forward keeps wall-clock times",
+			window:    ScheduleWindow{Weekdays: []time.Weekday{time.Sunday}, Start: "01:00", End: "04:00"},
+			from:      time.Date(2026, 3, 8, 0, 0, 0, 0, ny),
+			days:      1,
+			wantStart: []time.Time{time.Date(2026, 3, 8, 1, 0, 0, 0, ny)},
+			wantLen:   []time.Duration{2 * time.Hour},
+		},
+		{
+			name:      "fall back keeps wall-clock times",
+			window:    ScheduleWindow{Weekdays: []time.Weekday{time.Sunday}, Start: "00:30", End: "03:00"},
+			from:      time.Date(2026, 11, 1, 0, 0, 0, 0, ny),
+			days:      1,
+			wantStart: []time.Time{time.Date(2026, 11, 1, 0, 30, 0, 0, ny)},
+			wantLen:   []time.Duration{3*time.Hour + 30*time.Minute},
+		},
+		{
+			name:      "window past midnight ends the next day",
+			window:    ScheduleWindow{Weekdays: []time.Weekday{time.Friday}, Start: "22:00", End: "02:00"},
+			from:      time.Date(2026, 10, 12, 0, 0, 0, 0, ny),
+			days:      7,
+			wantStart: []time.Time{time.Date(2026, 10, 16, 22, 0, 0, 0, ny)},
+			wantLen:   []time.Duration{4 * time.Hour},
+		},
+		{
+			name:      "overnight window across spring forward",
+			window:    ScheduleWindow{Weekdays: []time.Weekday{time.Saturday}, Start: "23:00", End: "07:00"},
+			from:      time.Date(2026, 3, 7, 0, 0, 0, 0, ny),
+			days:      1,
+			wantStart: []time.Time{time.Date(2026, 3, 7, 23, 0, 0, 0, ny)},
+			wantLen:   []time.Duration{7 * time.Hour},
+		},
+		{
+			name:      "equal start and end is a full day",
+			window:    ScheduleWindow{Weekdays: []time.Weekday{time.Monday, time.Tuesday}, Start: "08:00", End: "08:00"},
+			from:      time.Date(2026, 10, 12, 0, 0, 0, 0, ny),
+			days:      7,
+			wantStart: []time.Time{time.Date(2026, 10, 12, 8, 0, 0, 0, ny), time.Date(2026, 10, 13, 8, 0, 0, 0, ny)},
+			wantLen:   []time.Duration{24 * time.Hour, 24 * time.Hour},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.window.occurrences(tt.from, tt.from.AddDate(0, 0, tt.days), ny)
+			if len(got) != len(tt.wantStart) {
+				t.Fatalf("got %d occurrences, want %d: %v", len(got), len(tt.wantStart), got)
+			}
+			for i, o := range got {
+				if !o[0].Equal(tt.wantStart[i]) {
+					t.Errorf("occurrence %d starts %v, want %v", i, o[0], tt.wantStart[i])
+				}
+				if d := o[1].Sub(o[0]); d != tt.wantLen[i] {
+					t.Errorf("occurrence %d lasts %v, want %v", i, d, tt.wantLen[i])
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleCalendarSkipsDisabledAndSorts(t *testing.T) {
+	loc := mustLoad(t, "Europe/Berlin")
+	rules := []BlockRule{
+		{ID: 1, Name: "evening", Enabled: true, Schedule: []ScheduleWindow{{Weekdays: []time.Weekday{time.Monday}, Start: "20:00", End: "22:00"}}},
+		{ID: 2, Name: "off", Enabled: false, Schedule: []ScheduleWindow{{Weekdays: []time.Weekday{time.Monday}, Start: "06:00", End: "07:00"}}},
+		{ID: 3, Name: "morning", Enabled: true, Schedule: []ScheduleWindow{{Weekdays: []time.Weekday{time.Monday}, Start: "07:00", End: "08:00"}}},
+	}
+	from := time.Date(2026, 10, 12, 0, 0, 0, 0, loc)
+	got := scheduleCalendar(rules, from, from.AddDate(0, 0, 7), loc)
+	if len(got) != 2 || got[0].RuleID != 3 || got[1].RuleID != 1 {
+		t.Fatalf("got %+v, want morning then evening", got)
+	}
+}
+
+func TestScheduleCalendarIncludesOvernightFromPreviousDay(t *testing.T) {
+	loc := mustLoad(t, "Europe/Berlin")
+	rules := []BlockRule{
+		{ID: 1, Name: "night", Enabled: true, Schedule: []ScheduleWindow{{Weekdays: []time.Weekday{time.Sunday}, Start: "22:00", End: "06:00"}}},
+	}
+	// Week starting Monday 12 Oct at a 4 AM day start: the window from the
+	// Sunday before runs until 06:00 Monday and belongs on the calendar,
+	// as does the one starting on the week's last Sunday.
+	from := time.Date(2026, 10, 12, 4, 0, 0, 0, loc)
+	got := scheduleCalendar(rules, from, from.AddDate(0, 0, 7), loc)
+	if len(got) != 2 {
+		t.Fatalf("got %d occurrences, want 2: %+v", len(got), got)
+	}
+	if want := time.Date(2026, 10, 11, 22, 0, 0, 0, loc); !got[0].Start.Equal(want) {
+		t.Errorf("first occurrence starts %v, want %v", got[0].Start, want)
+	}
+	if want := time.Date(2026, 10, 18, 22, 0, 0, 0, loc); !got[1].Start.Equal(want) {
+		t.Errorf("second occurrence starts %v, want %v", got[1].Start, want)
+	}
+
+	// A daily 01:00-03:00 window falls before the day start, so each
+	// reporting day's occurrence is on the next calendar day, up to and
+	// including 19 Oct, the calendar day the range ends on.
+	every := []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	rules = []BlockRule{
+		{ID: 2, Name: "early", Enabled: true, Schedule: []ScheduleWindow{{Weekdays: every, Start: "01:00", End: "03:00"}}},
+	}
+	got = scheduleCalendar(rules, from, from.AddDate(0, 0, 7), loc)
+	if len(got) != 7 {
+		t.Fatalf("got %d early occurrences, want 7: %+v", len(got), got)
+	}
+	if want := time.Date(2026, 10, 13, 1, 0, 0, 0, loc); !got[0].Start.Equal(want) {
+		t.Errorf("first early occurrence starts %v, want %v", got[0].Start, want)
+	}
+	if want := time.Date(2026, 10, 19, 1, 0, 0, 0, loc); !got[6].Start.Equal(want) {
+		t.Errorf("last early occurrence starts %v, want %v", got[6].Start, want)
+	}
+}
+
+func TestPathsOverlap(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{`C:\`, true},
+		{`c:\windows`, true},
+		{`C:\Windows\System32\`, true},
+		{`C:\Program Files\Common Files\`, true},
+		{`C:\Users\Kid\..\..\Windows`, true},
+		{`C:/Program Files`, true},
+		{`C:\Prog`, false},
+		{`C:\WindowsApps`, false},
+		{`C:\Users\Kid\Downloads`, false},
+		{`E:\`, false},
+	}
+	for _, tt := range tests {
+		got := pathsOverlap(`C:\Windows`, tt.pattern) || pathsOverlap(`C:\Program Files`, tt.pattern)
+		if got != tt.want {
+			t.Errorf("pathsOverlap(system dirs, %q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestValidateRuleProtectsSessionProcesses(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("systemd processes are only protected on Linux")
+	}
+	tests := []struct {
+		rule BlockRule
+		ok   bool
+	}{
+		{BlockRule{MatchType: MatchExact, Pattern: "systemd"}, false},
+		{BlockRule{MatchType: MatchExact, Pattern: "systemd-homed"}, false},
+		{BlockRule{MatchType: MatchGlob, Pattern: "systemd-*"}, false},
+		{BlockRule{MatchType: MatchExact, Pattern: "steam"}, true},
+	}
+	if shell := os.Getenv("SHELL"); shell != "" {
+		tests = append(tests, struct {
+			rule BlockRule
+			ok   bool
+		}{BlockRule{MatchType: MatchExact, Pattern: filepath.Base(shell)}, false})
+	}
+	for _, tt := range tests {
+		if err := validateRule(tt.rule); (err == nil) != tt.ok {
+			t.Errorf("validateRule(%s %q) = %v, want ok=%v", tt.rule.MatchType, tt.rule.Pattern, err, tt.ok)
+		}
+	}
+}
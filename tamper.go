@@ -0,0 +1,83 @@
+package main
+
+import (
+	"time"
+
+	"veda-anchor-ui/internal/clock"
+	"veda-anchor-ui/internal/i18n"
+	"veda-anchor-ui/internal/logging"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const (
+	clockCheckInterval = time.Minute
+	// clockJumpThreshold is how far the wall clock may fall behind elapsed
+	// time before it counts as being set back; small corrections from time
+	// sync stay well under it.
+	clockJumpThreshold = 5 * time.Minute
+)
+
+// tamperEvent is recorded in the agent's audit log and forwarded to the
+// admin like the agent's own detections (database deleted or truncated).
+type tamperEvent struct {
+	Kind       string    `json:"kind"`
+	DetectedAt time.Time `json:"detectedAt"`
+	Details    string    `json:"details"`
+}
+
+// clockWatch detects the system clock being set back to dodge time limits.
+// It compares the wall clock against the monotonic clock, which the user
+// can't change; forward jumps are ignored since sleep and hibernation
+// produce them legitimately.
+type clockWatch struct {
+	clock clock.Clock
+	wall  time.Time
+	mono  time.Duration
+}
+
+func newClockWatch(c clock.Clock) *clockWatch {
+	return &clockWatch{clock: c, wall: c.Now(), mono: c.Monotonic()}
+}
+
+// check returns how far the wall clock was set back since the last check,
+// or 0 if it wasn't (beyond clockJumpThreshold).
+func (w *clockWatch) check() time.Duration {
+	wall, mono := w.clock.Now(), w.clock.Monotonic()
+	skew := (mono - w.mono) - wall.Sub(w.wall)
+	w.wall, w.mono = wall, mono
+	if skew > clockJumpThreshold {
+		return skew
+	}
+	return 0
+}
+
+func (a *App) watchClock() {
+	ticker := time.NewTicker(clockCheckInterval)
+	defer ticker.Stop()
+	w := newClockWatch(a.clock)
+	for a.wait(ticker.C) {
+		if back := w.check(); back > 0 {
+			a.reportTamper(tamperEvent{
+				Kind:       "clock_backwards",
+				DetectedAt: a.clock.Now(),
+				Details:    "system clock moved back by " + back.Round(time.Second).String(),
+			})
+		}
+	}
+}
+
+func (a *App) reportTamper(e tamperEvent) {
+	logging.Component("tamper").Warn("Tampering detected", "kind", e.Kind, "details", e.Details)
+	if err := a.callVoid("RecordTamperEvent", e); err != nil {
+		logging.Component("tamper").Error("Failed to record tamper event", "error", err)
+	}
+	wailsruntime.EventsEmit(a.ctx, "tamper:detected", e)
+	a.notify("tamper", i18n.T("notify.tamper.title"), i18n.T("notify.tamper.body"))
+}
+
+// GetTamperEvents lists detected tampering (clock set back, database
+// deleted or truncated), newest first.
+func (a *App) GetTamperEvents(q PageQuery) (any, error) {
+	return a.callPage("GetTamperEvents", q, nil)
+}
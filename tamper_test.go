@@ -0,0 +1,38 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"veda-anchor-ui/internal/clock"
+)
+
+func TestClockWatch(t *testing.T) {
+	base := time.Date(2026, 10, 16, 21, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		step func(f *clock.Fake)
+		want time.Duration
+	}{
+		{"normal tick", func(f *clock.Fake) { f.Advance(time.Minute) }, 0},
+		{"suspend gap", func(f *clock.Fake) { f.Advance(time.Minute); f.Suspend(8 * time.Hour) }, 0},
+		{"clock set forward", func(f *clock.Fake) { f.Advance(time.Minute); f.Set(f.Now().Add(3 * time.Hour)) }, 0},
+		{"small sync correction", func(f *clock.Fake) { f.Advance(time.Minute); f.Set(f.Now().Add(-30 * time.Second)) }, 0},
+		{"clock set back", func(f *clock.Fake) { f.Advance(time.Minute); f.Set(f.Now().Add(-2 * time.Hour)) }, 2 * time.Hour},
+		{"set back across midnight", func(f *clock.Fake) { f.Advance(3 * time.Hour); f.Set(base.Add(-time.Hour)) }, 4 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := clock.NewFake(base)
+			w := newClockWatch(f)
+			tt.step(f)
+			if got := w.check(); got != tt.want {
+				t.Errorf("check() = %v, want %v", got, tt.want)
+			}
+			f.Advance(time.Minute)
+			if got := w.check(); got != 0 {
+				t.Errorf("second check() = %v, want 0", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"runtime"
+	"time"
+
+	"fyne.io/systray"
+
+	"veda-anchor-ui/internal/i18n"
+	"veda-anchor-ui/internal/logging"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+//go:embed build/windows/icon.ico
+var trayIconICO []byte
+
+//go:embed build/appicon.png
+var trayIconPNG []byte
+
+const trayRefreshInterval = time.Minute
+
+// trayAvailable is false on macOS, where AppKit only allows the status item
+// on the main thread and Wails already owns it. There the window minimises
+// to the Dock instead of hiding to the tray.
+const trayAvailable = runtime.GOOS != "darwin"
+
+// runTray owns the system tray icon. On Windows the tray window and its
+// message loop must live on one OS thread, so this goroutine is locked to
+// its thread for the tray's lifetime.
+func (a *App) runTray() {
+	if !trayAvailable {
+		logging.Component("tray").Info("System tray is not supported on this platform")
+		return
+	}
+	runtime.LockOSThread()
+	systray.Run(a.onTrayReady, nil)
+}
+
+func (a *App) onTrayReady() {
+	if runtime.GOOS == "windows" {
+		systray.SetIcon(trayIconICO)
+	} else {
+		systray.SetIcon(trayIconPNG)
+	}
+	systray.SetTooltip("Veda Anchor")
+
+	show := systray.AddMenuItem(i18n.T("tray.show"), "")
+	hide := systray.AddMenuItem(i18n.T("tray.hide"), "")
+	systray.AddSeparator()
+	today := systray.AddMenuItem(i18n.T("tray.today", "…"), "")
+	today.Disable()
+	pause := systray.AddMenuItem(i18n.T("tray.pause"), "")
+	systray.AddSeparator()
+	quit := systray.AddMenuItem(i18n.T("tray.quit"), "")
+
+	refresh := time.NewTicker(trayRefreshInterval)
+	defer refresh.Stop()
+	a.refreshTrayScreenTime(today)
+	for {
+		select {
+		case <-show.ClickedCh:
+			a.ShowWindow()
+		case <-hide.ClickedCh:
+			a.hideWindow()
+		case <-pause.ClickedCh:
+			if err := a.PauseMonitoring(60); err != nil {
+				logging.Component("tray").Warn("Pause from tray failed", "error", err)
+				// Most likely the admin PIN is required; let the UI ask.
+				a.ShowWindow()
+				wailsruntime.EventsEmit(a.ctx, "tray:pause-failed", err.Error())
+			}
+		case <-quit.ClickedCh:
+			// Remove the icon first, or it lingers until the mouse passes
+			// over it.
+			systray.Quit()
+			a.Quit()
+			return
+		case <-refresh.C:
+			a.refreshTrayScreenTime(today)
+		case <-a.stop:
+			systray.Quit()
+			return
+		}
+	}
+}
+
+func (a *App) refreshTrayScreenTime(item *systray.MenuItem) {
+	res, err := a.ipcClient.Request("GetTotalScreenTime", nil)
+	if err != nil {
+		return
+	}
+	secs, err := unmarshalResult[int64](res)
+	if err != nil {
+		return
+	}
+	d := time.Duration(secs) * time.Second
+	item.SetTitle(i18n.T("tray.today", fmt.Sprintf("%dh %02dm", int(d.Hours()), int(d.Minutes())%60)))
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"veda-anchor-ui/internal/logging"
+	"veda-anchor-ui/internal/update"
+
+	wailsruntime "github.com/wailsapp/wails/v2/pkg/runtime"
+)
+
+const updateCheckInterval = 6 * time.Hour
+
+// updater tracks the newest release found and the verified installer once
+// it has been downloaded, with the release it was verified against.
+type updater struct {
+	mu        sync.Mutex
+	client    *http.Client
+	available *update.Release
+	installer string
+	verified  *update.Release
+}
+
+func newUpdater() *updater {
+	return &updater{client: &http.Client{Timeout: 5 * time.Minute}}
+}
+
+// CheckForUpdate looks for a newer release on the configured channel and
+// returns it, or nil when up to date or updates are off.
+func (a *App) CheckForUpdate() (*update.Release, error) {
+	channel := a.settings.Get().UpdateChannel
+	if channel == "off" {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, time.Minute)
+	defer cancel()
+	rel, err := update.Latest(ctx, a.updater.client, channel, runtime.GOARCH)
+	if err != nil {
+		return nil, err
+	}
+	if rel == nil || !update.Newer(version, rel.Version) {
+		return nil, nil
+	}
+	a.updater.mu.Lock()
+	a.updater.available = rel
+	a.updater.mu.Unlock()
+	return rel, nil
+}
+
+// DownloadUpdate downloads and verifies the release found by CheckForUpdate.
+// It is installed by InstallUpdate or the next time the UI quits.
+func (a *App) DownloadUpdate() error {
+	a.updater.mu.Lock()
+	rel := a.updater.available
+	a.updater.mu.Unlock()
+	if rel == nil {
+		return fmt.Errorf("no update available")
+	}
+	path, err := update.Download(a.ctx, a.updater.client, rel, filepath.Join(os.TempDir(), "veda-anchor-update"))
+	if err != nil {
+		return err
+	}
+	a.updater.mu.Lock()
+	a.updater.installer = path
+	a.updater.verified = rel
+	a.updater.mu.Unlock()
+	wailsruntime.EventsEmit(a.ctx, "update:ready", rel)
+	return nil
+}
+
+// InstallUpdate quits the UI and runs the verified installer silently; the
+// installer relaunches the new version.
+func (a *App) InstallUpdate() error {
+	if err := a.launchInstaller(); err != nil {
+		return err
+	}
+	a.Quit()
+	return nil
+}
+
+// launchInstaller starts the downloaded installer, if any, detached from
+// this process so it can replace our executable. The signature is checked
+// again first, since the file has sat in the temp folder since download.
+func (a *App) launchInstaller() error {
+	a.updater.mu.Lock()
+	path, rel := a.updater.installer, a.updater.verified
+	a.updater.installer, a.updater.verified = "", nil
+	a.updater.mu.Unlock()
+	if path == "" {
+		return fmt.Errorf("no update downloaded")
+	}
+	if err := update.Verify(path, rel); err != nil {
+		_ = os.Remove(path)
+		return err
+	}
+	return exec.Command(path, "/S").Start()
+}
+
+// watchUpdates checks for updates periodically and emits "update:available"
+// the first time each new version is seen.
+func (a *App) watchUpdates() {
+	var announced string
+	ticker := time.NewTicker(updateCheckInterval)
+	defer ticker.Stop()
+	for ok := true; ok; ok = a.wait(ticker.C) {
+		rel, err := a.CheckForUpdate()
+		if err != nil {
+			logging.Component("update").Info("Update check failed", "error", err)
+			continue
+		}
+		if rel != nil && rel.Version != announced {
+			announced = rel.Version
+			wailsruntime.EventsEmit(a.ctx, "update:available", rel)
+		}
+	}
+}